@@ -3,7 +3,7 @@ package config
 var (
 	GIT          = "git"
 	GitUrl       = "http://example.com/api/"
-	APIVersion   = "v3"
+	APIVersion   = "auto"
 	AdminToken   = "yourgitlabtoken"
 	NamespaceId  = "1229"
 	GitDeployDir = "/home/myname/tmp/"
@@ -0,0 +1,113 @@
+package gitlab
+
+import (
+	"context"
+	"strconv"
+)
+
+//MergeRequestApprovals 合并请求的审批状态
+type MergeRequestApprovals struct {
+	Approved          bool       `json:"approved"`
+	ApprovalsRequired int        `json:"approvals_required"`
+	ApprovalsLeft     int        `json:"approvals_left"`
+	ApprovedBy        []Approver `json:"approved_by"`
+	UserHasApproved   bool       `json:"user_has_approved"` //当前用户是否已审批
+	UserCanApprove    bool       `json:"user_can_approve"`
+}
+
+//Approver 审批人
+type Approver struct {
+	User User `json:"user"`
+}
+
+//ApprovalRule 审批规则，需要Gitlab Premium
+type ApprovalRule struct {
+	Id                int                `json:"id"`
+	Name              string             `json:"name"`
+	RuleType          string             `json:"rule_type"` //regular、code_owner、any_approver等
+	ApprovalsRequired int                `json:"approvals_required"`
+	EligibleApprovers []User             `json:"eligible_approvers"`
+	Users             []User             `json:"users"`
+	Groups            []ProjectNamespace `json:"groups"`
+	Approved          bool               `json:"approved"` //只有合并请求的规则返回
+	ApprovedBy        []User             `json:"approved_by"`
+}
+
+//ApprovalRuleOptions 创建或更新审批规则的参数，更新时为空的字段不修改
+type ApprovalRuleOptions struct {
+	Name              string `json:"name,omitempty"`
+	ApprovalsRequired *int   `json:"approvals_required,omitempty"` //创建时必须设置，可以为0
+	UserIds           []int  `json:"user_ids,omitempty"`
+	GroupIds          []int  `json:"group_ids,omitempty"`
+}
+
+//GetMergeRequestApprovals 获取合并请求的审批状态
+func (c *Client) GetMergeRequestApprovals(ctx context.Context, projectId string, mergeRequestIid int, opts ...RequestOption) (approvals *MergeRequestApprovals, err error) {
+	return doJSON[MergeRequestApprovals](ctx, c, "GET", mergeRequestPath(projectId, mergeRequestIid)+"/approvals", nil, opts)
+}
+
+//ApproveMergeRequest 审批通过合并请求，sha不为空时源分支的最新提交不是sha则失败，避免审批未看过的提交
+func (c *Client) ApproveMergeRequest(ctx context.Context, projectId string, mergeRequestIid int, sha string, opts ...RequestOption) (approvals *MergeRequestApprovals, err error) {
+	var body interface{}
+	if sha != "" {
+		body = map[string]string{"sha": sha}
+	}
+	return doJSON[MergeRequestApprovals](ctx, c, "POST", mergeRequestPath(projectId, mergeRequestIid)+"/approve", body, opts)
+}
+
+//UnapproveMergeRequest 撤销当前用户的审批
+func (c *Client) UnapproveMergeRequest(ctx context.Context, projectId string, mergeRequestIid int, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "POST", mergeRequestPath(projectId, mergeRequestIid)+"/unapprove", opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//ListMergeRequestApprovalRules 列出合并请求的审批规则
+func (c *Client) ListMergeRequestApprovalRules(ctx context.Context, projectId string, mergeRequestIid int, opts ...RequestOption) (rules []ApprovalRule, err error) {
+	return listAll[ApprovalRule](ctx, c, mergeRequestPath(projectId, mergeRequestIid)+"/approval_rules", ListOptions{}, nil, opts)
+}
+
+//CreateMergeRequestApprovalRule 为合并请求添加审批规则
+func (c *Client) CreateMergeRequestApprovalRule(ctx context.Context, projectId string, mergeRequestIid int, opt ApprovalRuleOptions, opts ...RequestOption) (rule *ApprovalRule, err error) {
+	return doJSON[ApprovalRule](ctx, c, "POST", mergeRequestPath(projectId, mergeRequestIid)+"/approval_rules", opt, opts)
+}
+
+//UpdateMergeRequestApprovalRule 修改合并请求的审批规则
+func (c *Client) UpdateMergeRequestApprovalRule(ctx context.Context, projectId string, mergeRequestIid, ruleId int, opt ApprovalRuleOptions, opts ...RequestOption) (rule *ApprovalRule, err error) {
+	return doJSON[ApprovalRule](ctx, c, "PUT", mergeRequestPath(projectId, mergeRequestIid)+"/approval_rules/"+strconv.Itoa(ruleId), opt, opts)
+}
+
+//DeleteMergeRequestApprovalRule 删除合并请求的审批规则
+func (c *Client) DeleteMergeRequestApprovalRule(ctx context.Context, projectId string, mergeRequestIid, ruleId int, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", mergeRequestPath(projectId, mergeRequestIid)+"/approval_rules/"+strconv.Itoa(ruleId), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//ListProjectApprovalRules 列出项目的审批规则，新建的合并请求会继承这些规则
+func (c *Client) ListProjectApprovalRules(ctx context.Context, projectId string, opts ...RequestOption) (rules []ApprovalRule, err error) {
+	return listAll[ApprovalRule](ctx, c, projectPath(projectId)+"/approval_rules", ListOptions{}, nil, opts)
+}
+
+//CreateProjectApprovalRule 为项目添加审批规则
+func (c *Client) CreateProjectApprovalRule(ctx context.Context, projectId string, opt ApprovalRuleOptions, opts ...RequestOption) (rule *ApprovalRule, err error) {
+	return doJSON[ApprovalRule](ctx, c, "POST", projectPath(projectId)+"/approval_rules", opt, opts)
+}
+
+//UpdateProjectApprovalRule 修改项目的审批规则
+func (c *Client) UpdateProjectApprovalRule(ctx context.Context, projectId string, ruleId int, opt ApprovalRuleOptions, opts ...RequestOption) (rule *ApprovalRule, err error) {
+	return doJSON[ApprovalRule](ctx, c, "PUT", projectPath(projectId)+"/approval_rules/"+strconv.Itoa(ruleId), opt, opts)
+}
+
+//DeleteProjectApprovalRule 删除项目的审批规则
+func (c *Client) DeleteProjectApprovalRule(ctx context.Context, projectId string, ruleId int, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", projectPath(projectId)+"/approval_rules/"+strconv.Itoa(ruleId), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
@@ -0,0 +1,446 @@
+package gitlab
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"config"
+	"util"
+)
+
+//ArchiveOptions 下载仓库归档的参数
+type ArchiveOptions struct {
+	Format          string //tar.gz（默认）、tar.bz2、tar或zip
+	Path            string //只下载该子目录，只支持v4
+	StripComponents int    //解压时去掉路径的前几级，Gitlab生成的归档都有一级 项目名-ref-sha 目录
+}
+
+//Archive 下载的归档信息
+type Archive struct {
+	Format   string
+	Filename string //Gitlab返回的文件名
+	Size     int64  //归档的字节数
+	SHA256   string //归档内容的sha256，十六进制
+	Dir      string //解压的目录，只有ExtractArchive时有值
+	Files    int    //解压的文件数，只有ExtractArchive时有值
+}
+
+func (o ArchiveOptions) format() string {
+	switch o.Format {
+	case "":
+		return "tar.gz"
+	case "tgz":
+		return "tar.gz"
+	case "tbz", "tbz2", "tb2", "bz2":
+		return "tar.bz2"
+	}
+	return o.Format
+}
+
+//请求归档，sha为空时使用默认分支
+func (c *Client) archive(ctx context.Context, projectId, sha string, opt ArchiveOptions, opts []RequestOption) (body io.ReadCloser, archive *Archive, err error) {
+	format := opt.format()
+
+	req := c.newRequest(ctx, "GET", projectPath(projectId)+"/repository/archive."+format, opts)
+	if sha != "" {
+		req.Param("sha", sha)
+	}
+	if opt.Path != "" {
+		req.Param("path", opt.Path)
+	}
+
+	body, resp, err := req.Stream()
+	if err != nil {
+		return
+	}
+
+	archive = &Archive{Format: format}
+	if disposition := resp.Header.Get("Content-Disposition"); disposition != "" {
+		if i := strings.Index(disposition, "filename="); i >= 0 {
+			archive.Filename = strings.Trim(disposition[i+len("filename="):], `"`)
+		}
+	}
+	return
+}
+
+/*
+DownloadArchive 下载ref（分支名、tag或commit id）的归档并写入w，返回归档的大小和sha256
+用法：
+	f, _ := os.Create("/tmp/app.tar.gz")
+	defer f.Close()
+	archive, err := client.DownloadArchive(ctx, projectId, "v1.0.0", f, gitlab.ArchiveOptions{})
+*/
+func (c *Client) DownloadArchive(ctx context.Context, projectId, ref string, w io.Writer, opt ArchiveOptions, opts ...RequestOption) (archive *Archive, err error) {
+	body, archive, err := c.archive(ctx, projectId, ref, opt, opts)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	h := sha256.New()
+	if archive.Size, err = io.Copy(io.MultiWriter(w, h), body); err != nil {
+		archive = nil
+		return
+	}
+	archive.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return
+}
+
+/*
+ExtractArchive 下载ref的归档并解压到config.GitDeployDir下的dir目录，不需要clone整个仓库
+归档中的绝对路径、包含..的路径以及指向目录之外的链接（包括借助其它链接跳出的）都会导致解压失败，不会写入dir之外的文件
+*/
+func (c *Client) ExtractArchive(ctx context.Context, projectId, ref, dir string, opt ArchiveOptions, opts ...RequestOption) (archive *Archive, err error) {
+	dest, err := securePath(filepath.Clean(config.GitDeployDir), dir)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(dest, 0755); err != nil {
+		return
+	}
+
+	body, archive, err := c.archive(ctx, projectId, ref, opt, opts)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	h := sha256.New()
+	counter := &countWriter{Hash: h}
+	src := io.TeeReader(body, counter)
+
+	x := &extractor{dest: dest, strip: opt.StripComponents}
+	switch archive.Format {
+	case "zip":
+		err = x.zip(src)
+	case "tar.gz":
+		var gz *gzip.Reader
+		if gz, err = gzip.NewReader(src); err == nil {
+			err = x.tar(gz)
+		}
+	case "tar.bz2":
+		err = x.tar(bzip2.NewReader(src))
+	case "tar":
+		err = x.tar(src)
+	default:
+		err = util.NewError("unsupported archive format: %s", archive.Format)
+	}
+
+	//解压完成后可能还有填充数据未读取，全部读完才能得到完整的sha256
+	if err == nil {
+		_, err = io.Copy(io.Discard, src)
+	}
+	if err != nil {
+		archive = nil
+		return
+	}
+
+	archive.Size = counter.n
+	archive.SHA256 = hex.EncodeToString(h.Sum(nil))
+	archive.Dir = dest
+	archive.Files = x.files
+	return
+}
+
+//计算sha256的同时统计字节数
+type countWriter struct {
+	hash.Hash
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return w.Hash.Write(p)
+}
+
+//返回root下的name，name为绝对路径或包含..跳出root时返回错误
+func securePath(root, name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") || strings.HasPrefix(name, `\`) {
+		return "", util.NewError("illegal path: %s is outside of %s", name, root)
+	}
+
+	target := filepath.Join(root, name)
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", util.NewError("illegal path: %s is outside of %s", name, root)
+	}
+	return target, nil
+}
+
+//将归档中的文件写入dest
+type extractor struct {
+	dest  string
+	strip int
+	files int
+}
+
+//去掉路径的前strip级，返回空字符串时跳过该条目
+func (x *extractor) target(name string) (string, error) {
+	name = strings.TrimSuffix(filepath.ToSlash(name), "/")
+	parts := strings.Split(name, "/")
+	if len(parts) <= x.strip {
+		return "", nil
+	}
+	return securePath(x.dest, filepath.FromSlash(strings.Join(parts[x.strip:], "/")))
+}
+
+func (x *extractor) tar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := x.target(header.Name)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = x.mkdir(target)
+		case tar.TypeReg:
+			err = x.writeFile(target, tr, os.FileMode(header.Mode))
+		case tar.TypeSymlink:
+			err = x.symlink(target, header.Linkname)
+		case tar.TypeXGlobalHeader:
+			//git archive写入的commit id
+		default:
+			err = util.NewError("unsupported entry in archive: %s", header.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+//zip需要随机读取，先写入临时文件
+func (x *extractor) zip(r io.Reader) error {
+	tmp, err := os.CreateTemp("", "gitlab-archive-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(tmp, size)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		target, err := x.target(f.Name)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			continue
+		}
+
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = x.mkdir(target)
+		case mode&os.ModeSymlink != 0:
+			err = x.zipSymlink(target, f)
+		default:
+			err = x.zipFile(target, f)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *extractor) zipFile(target string, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return x.writeFile(target, rc, f.Mode())
+}
+
+//zip中链接的目标保存在内容中
+func (x *extractor) zipSymlink(target string, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	linkname, err := io.ReadAll(io.LimitReader(rc, 4096))
+	if err != nil {
+		return err
+	}
+	return x.symlink(target, string(linkname))
+}
+
+func (x *extractor) mkdir(target string) error {
+	if err := x.checkParents(target); err != nil {
+		return err
+	}
+	return os.MkdirAll(target, 0755)
+}
+
+func (x *extractor) writeFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := x.checkParents(target); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	perm := os.FileMode(0644)
+	if mode&0111 != 0 {
+		perm = 0755
+	}
+
+	//目标已存在时先删除，避免通过已有的链接写到dest之外
+	os.Remove(target)
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	x.files++
+	return nil
+}
+
+//链接只能指向dest内的相对路径，按照磁盘上已解压的链接解析目标，避免借助多个链接跳出dest
+func (x *extractor) symlink(target, linkname string) error {
+	if filepath.IsAbs(linkname) || strings.HasPrefix(linkname, "/") || strings.HasPrefix(linkname, `\`) {
+		return util.NewError("illegal link in archive: %s -> %s", target, linkname)
+	}
+
+	if err := x.checkParents(target); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if _, err := x.resolve(filepath.Dir(target), linkname, 0); err != nil {
+		return util.NewError("illegal link in archive: %s -> %s", target, linkname)
+	}
+
+	//已有的目录不能替换为链接，否则之前经过该目录的链接会指向别处
+	if info, err := os.Lstat(target); err == nil && info.IsDir() {
+		return util.NewError("illegal link in archive, %s is a directory", target)
+	}
+
+	os.Remove(target)
+	return os.Symlink(linkname, target)
+}
+
+//链接最多嵌套的层数
+const maxLinkDepth = 40
+
+/*
+从dest内的目录dir开始解析链接的目标，遇到已解压的链接时继续解析该链接，返回解析后的路径
+..只能用在磁盘上已存在的目录之后，否则之后创建的链接可能改变..的含义；解析过程中跳出dest时返回错误
+*/
+func (x *extractor) resolve(dir, linkname string, depth int) (string, error) {
+	if depth > maxLinkDepth {
+		return "", util.NewError("too many levels of links: %s", linkname)
+	}
+	if filepath.IsAbs(linkname) {
+		return "", util.NewError("illegal path: %s is outside of %s", linkname, x.dest)
+	}
+
+	current := dir
+	exists := true
+	for _, part := range strings.Split(filepath.ToSlash(linkname), "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if !exists || current == x.dest {
+				return "", util.NewError("illegal path: %s is outside of %s", linkname, x.dest)
+			}
+			current = filepath.Dir(current)
+			continue
+		}
+
+		current = filepath.Join(current, part)
+		if !exists {
+			continue
+		}
+
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			exists = false
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			next, err := os.Readlink(current)
+			if err != nil {
+				return "", err
+			}
+			if current, err = x.resolve(filepath.Dir(current), next, depth+1); err != nil {
+				return "", err
+			}
+		}
+	}
+	return current, nil
+}
+
+//target的上级目录中不能有链接，否则可能借助链接写到dest之外
+func (x *extractor) checkParents(target string) error {
+	rel, err := filepath.Rel(x.dest, filepath.Dir(target))
+	if err != nil || rel == "." {
+		return err
+	}
+
+	dir := x.dest
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		dir = filepath.Join(dir, part)
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return util.NewError("illegal path in archive, parent is a link: %s", target)
+		}
+	}
+	return nil
+}
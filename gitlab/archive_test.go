@@ -0,0 +1,207 @@
+package gitlab
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//归档中的一个条目，linkname不为空时为链接，name以/结尾时为目录
+type tarEntry struct {
+	name     string
+	linkname string
+	content  string
+}
+
+func buildTar(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0644}
+		switch {
+		case e.linkname != "":
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = e.linkname
+		case strings.HasSuffix(e.name, "/"):
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0755
+		default:
+			header.Typeflag = tar.TypeReg
+			header.Size = int64(len(e.content))
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(e.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+//在临时目录下解压，dest是其中的子目录，便于检查是否写到了dest之外
+func extractTar(t *testing.T, entries []tarEntry) (dest string, x *extractor, err error) {
+	t.Helper()
+
+	dest = filepath.Join(t.TempDir(), "deploy", "app")
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		t.Fatal(err)
+	}
+	x = &extractor{dest: dest}
+	err = x.tar(buildTar(t, entries))
+	return
+}
+
+func TestExtractTar(t *testing.T) {
+	dest, x, err := extractTar(t, []tarEntry{
+		{name: "conf/"},
+		{name: "conf/app.yml", content: "port: 80"},
+		{name: "bin/run.sh", content: "#!/bin/sh"},
+		{name: "conf/current.yml", linkname: "app.yml"},
+		{name: "bin/conf", linkname: "../conf"},
+		{name: "latest", linkname: "bin/conf/../run.sh"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.files != 2 {
+		t.Errorf("files = %d, want 2", x.files)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dest, "bin", "conf", "current.yml"))
+	if err != nil || string(data) != "port: 80" {
+		t.Errorf("read through links = %q, %v", data, err)
+	}
+}
+
+func TestExtractTarStrip(t *testing.T) {
+	dest, _, err := extractTar(t, []tarEntry{
+		{name: "app-master-abc/"},
+		{name: "app-master-abc/README.md", content: "readme"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(dest, "app-master-abc", "README.md")); err != nil {
+		t.Error(err)
+	}
+
+	dest = filepath.Join(t.TempDir(), "app")
+	x := &extractor{dest: dest, strip: 1}
+	if err = x.tar(buildTar(t, []tarEntry{
+		{name: "app-master-abc/"},
+		{name: "app-master-abc/README.md", content: "readme"},
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(dest, "README.md")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExtractTarRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+	}{
+		{"absolute path", []tarEntry{
+			{name: "/tmp/evil", content: "x"},
+		}},
+		{"dotdot path", []tarEntry{
+			{name: "../evil", content: "x"},
+		}},
+		{"dotdot in the middle", []tarEntry{
+			{name: "a/../../evil", content: "x"},
+		}},
+		{"absolute link", []tarEntry{
+			{name: "passwd", linkname: "/etc/passwd"},
+		}},
+		{"dotdot link", []tarEntry{
+			{name: "a/up", linkname: "../../.."},
+		}},
+		{"file under link parent", []tarEntry{
+			{name: "sub/"},
+			{name: "link", linkname: "sub"},
+			{name: "link/evil", content: "x"},
+		}},
+		{"dir under link parent", []tarEntry{
+			{name: "sub/"},
+			{name: "link", linkname: "sub"},
+			{name: "link/dir/"},
+		}},
+		{"chained links", []tarEntry{
+			{name: "d1/d2/l2", linkname: "../.."},
+			{name: "l1", linkname: "d1/d2/l2/../.."},
+		}},
+		{"link chain through a link to a link", []tarEntry{
+			{name: "a/b/"},
+			{name: "a/b/top", linkname: "../.."},
+			{name: "a/x", linkname: "b/top"},
+			{name: "escape", linkname: "a/x/.."},
+		}},
+		{"dotdot after missing dir", []tarEntry{
+			{name: "l1", linkname: "d/x/.."},
+			{name: "d/x", linkname: ".."},
+		}},
+		{"directory replaced by link", []tarEntry{
+			{name: "d/x/"},
+			{name: "l1", linkname: "d/x/../.."},
+			{name: "d/x", linkname: ".."},
+		}},
+		{"link loop", []tarEntry{
+			{name: "a", linkname: "b"},
+			{name: "b", linkname: "a"},
+			{name: "c", linkname: "a/.."},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest, _, err := extractTar(t, tt.entries)
+			if err == nil {
+				t.Fatalf("extract succeeded, want error")
+			}
+
+			//dest的上级目录中不能出现任何解压的文件
+			parent := filepath.Dir(dest)
+			entries, _ := os.ReadDir(parent)
+			if len(entries) != 1 {
+				t.Errorf("files written outside of dest: %v", entries)
+			}
+		})
+	}
+}
+
+func TestSecurePath(t *testing.T) {
+	root := filepath.FromSlash("/deploy")
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"app", true},
+		{"app/conf", true},
+		{"a/../b", true},
+		{"..", false},
+		{"../app", false},
+		{"a/../../b", false},
+		{"/etc", false},
+		{`\etc`, false},
+	}
+
+	for _, tt := range tests {
+		_, err := securePath(root, filepath.FromSlash(tt.name))
+		if (err == nil) != tt.ok {
+			t.Errorf("securePath(%q) error = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
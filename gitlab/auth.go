@@ -0,0 +1,181 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+//Authenticator 为请求添加认证信息
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+//TokenRefresher 可以刷新Token的认证方式，请求返回401时刷新后重试一次
+type TokenRefresher interface {
+	Refresh(ctx context.Context) error
+}
+
+//PrivateToken 使用PRIVATE-TOKEN Header认证，个人Access Token也使用该方式
+type PrivateToken string
+
+func (t PrivateToken) Authenticate(ctx context.Context, req *http.Request) error {
+	req.Header.Set("PRIVATE-TOKEN", string(t))
+	return nil
+}
+
+//JobToken 在CI Job中使用CI_JOB_TOKEN认证
+type JobToken string
+
+func (t JobToken) Authenticate(ctx context.Context, req *http.Request) error {
+	req.Header.Set("JOB-TOKEN", string(t))
+	return nil
+}
+
+//OAuthToken 使用OAuth2 Bearer Token认证，Token过期时通过refresh回调获取新的Token
+type OAuthToken struct {
+	mu      sync.Mutex
+	token   string
+	refresh func(ctx context.Context) (string, error)
+}
+
+//NewOAuthToken 创建OAuth2认证，refresh为nil时不刷新Token
+func NewOAuthToken(accessToken string, refresh func(ctx context.Context) (string, error)) *OAuthToken {
+	return &OAuthToken{
+		token:   accessToken,
+		refresh: refresh,
+	}
+}
+
+//Token 返回当前使用的Access Token
+func (t *OAuthToken) Token() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.token
+}
+
+func (t *OAuthToken) Authenticate(ctx context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+t.Token())
+	return nil
+}
+
+//Refresh 调用refresh回调更新Token
+func (t *OAuthToken) Refresh(ctx context.Context) error {
+	if t.refresh == nil {
+		return nil
+	}
+
+	token, err := t.refresh(ctx)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.token = token
+	t.mu.Unlock()
+	return nil
+}
+
+/*
+PasswordCredentials 只有用户名和密码时使用，通过OAuth2 password grant换取Access Token，
+Token在第一次请求时获取并缓存，返回401时重新获取
+*/
+type PasswordCredentials struct {
+	TokenURL   string       //获取Token的地址，例如 http://example.com/oauth/token
+	Username   string       //用户名或邮箱
+	Password   string       //密码
+	HTTPClient *http.Client //为nil时使用默认设置
+
+	mu    sync.Mutex
+	token string
+}
+
+//NewPasswordCredentials 根据Client的BaseURL推导获取Token的地址
+func NewPasswordCredentials(baseURL, username, password string) *PasswordCredentials {
+	root := strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/api")
+
+	return &PasswordCredentials{
+		TokenURL: root + "/oauth/token",
+		Username: username,
+		Password: password,
+	}
+}
+
+func (p *PasswordCredentials) Authenticate(ctx context.Context, req *http.Request) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token == "" {
+		token, err := p.login(ctx)
+		if err != nil {
+			return err
+		}
+		p.token = token
+	}
+
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	return nil
+}
+
+//Refresh 丢弃缓存的Token，下一次请求时重新登录
+func (p *PasswordCredentials) Refresh(ctx context.Context) error {
+	p.mu.Lock()
+	p.token = ""
+	p.mu.Unlock()
+	return nil
+}
+
+//使用用户名和密码换取Access Token
+func (p *PasswordCredentials) login(ctx context.Context) (token string, err error) {
+	form := url.Values{}
+	form.Set("grant_type", "password")
+	form.Set("username", p.Username)
+	form.Set("password", p.Password)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	hc := p.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Transport: defaultTransport}
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode != 200 {
+		err = newAPIError(req, resp, body)
+		return
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.Unmarshal(body, &result); err != nil {
+		return
+	}
+	token = result.AccessToken
+	return
+}
+
+//WithAuth 单个请求使用指定的认证方式，代替Client.Auth
+func WithAuth(auth Authenticator) RequestOption {
+	return func(o *requestOptions) {
+		o.auth = auth
+	}
+}
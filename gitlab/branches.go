@@ -0,0 +1,197 @@
+package gitlab
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+//Branch 分支信息
+type Branch struct {
+	Name               string `json:"name"`
+	Merged             bool   `json:"merged"`
+	Protected          bool   `json:"protected"`
+	Default            bool   `json:"default"`
+	DevelopersCanPush  bool   `json:"developers_can_push"`
+	DevelopersCanMerge bool   `json:"developers_can_merge"`
+	CanPush            bool   `json:"can_push"`
+	WebUrl             string `json:"web_url"`
+	Commit             Commit `json:"commit"`
+}
+
+//AccessLevel 项目成员的权限级别
+type AccessLevel int
+
+const (
+	NoAccess         AccessLevel = 0
+	GuestAccess      AccessLevel = 10
+	ReporterAccess   AccessLevel = 20
+	DeveloperAccess  AccessLevel = 30
+	MaintainerAccess AccessLevel = 40
+	OwnerAccess      AccessLevel = 50
+	AdminAccess      AccessLevel = 60
+)
+
+//BranchAccess 受保护分支的一条权限设置
+type BranchAccess struct {
+	AccessLevel            AccessLevel `json:"access_level"`
+	AccessLevelDescription string      `json:"access_level_description"`
+	UserId                 int         `json:"user_id"`
+	GroupId                int         `json:"group_id"`
+}
+
+//ProtectedBranch 受保护分支的设置
+type ProtectedBranch struct {
+	Id                    int            `json:"id"`
+	Name                  string         `json:"name"`
+	PushAccessLevels      []BranchAccess `json:"push_access_levels"`
+	MergeAccessLevels     []BranchAccess `json:"merge_access_levels"`
+	UnprotectAccessLevels []BranchAccess `json:"unprotect_access_levels"`
+	AllowForcePush        bool           `json:"allow_force_push"`
+}
+
+//ListBranchesOptions 分支列表的过滤条件
+type ListBranchesOptions struct {
+	ListOptions
+	Search string //按名称搜索，支持^开头和$结尾
+}
+
+func (o ListBranchesOptions) setParams(req *request) {
+	if o.Search != "" {
+		req.Param("search", o.Search)
+	}
+}
+
+//ListBranches 列出项目的分支，自动获取所有分页
+func (c *Client) ListBranches(ctx context.Context, projectId string, opt ListBranchesOptions, opts ...RequestOption) (branches []Branch, err error) {
+	return listAll[Branch](ctx, c, projectPath(projectId)+"/repository/branches", opt.ListOptions, opt.setParams, opts)
+}
+
+//ListBranchesPage 获取分支列表的一页数据
+func (c *Client) ListBranchesPage(ctx context.Context, projectId string, opt ListBranchesOptions, opts ...RequestOption) (branches []Branch, pageInfo *PageInfo, err error) {
+	return listPage[Branch](ctx, c, projectPath(projectId)+"/repository/branches", opt.ListOptions, opt.setParams, opts)
+}
+
+//GetBranch 获取单个分支的信息
+func (c *Client) GetBranch(ctx context.Context, projectId, branchName string, opts ...RequestOption) (branch *Branch, err error) {
+	return doJSON[Branch](ctx, c, "GET", branchPath(projectId, branchName), nil, opts)
+}
+
+//CreateBranch 从ref（分支名、tag或commit id）创建新分支
+func (c *Client) CreateBranch(ctx context.Context, projectId, branchName, ref string, opts ...RequestOption) (branch *Branch, err error) {
+	body := map[string]string{
+		branchParam(c.isV4(ctx)): branchName,
+		"ref":                    ref,
+	}
+	return doJSON[Branch](ctx, c, "POST", projectPath(projectId)+"/repository/branches", body, opts)
+}
+
+//DeleteBranch 删除分支
+func (c *Client) DeleteBranch(ctx context.Context, projectId, branchName string, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", branchPath(projectId, branchName), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//DeleteMergedBranches 删除所有已合并到默认分支的分支，受保护分支不会被删除，只支持v4
+func (c *Client) DeleteMergedBranches(ctx context.Context, projectId string, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", projectPath(projectId)+"/repository/merged_branches", opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//ProtectBranchOptions 保护分支的参数，权限级别为nil时使用Gitlab的默认值（Maintainer）
+type ProtectBranchOptions struct {
+	PushAccessLevel      *AccessLevel
+	MergeAccessLevel     *AccessLevel
+	UnprotectAccessLevel *AccessLevel
+	AllowForcePush       bool
+}
+
+//Access 返回level的指针，用于设置ProtectBranchOptions
+func Access(level AccessLevel) *AccessLevel {
+	return &level
+}
+
+/*
+ProtectBranch 保护分支，name可以是通配符，例如 release/*
+v3只能设置Developer是否可以push和merge，权限级别不高于Developer时视为允许
+*/
+func (c *Client) ProtectBranch(ctx context.Context, projectId, name string, opt ProtectBranchOptions, opts ...RequestOption) (protected *ProtectedBranch, err error) {
+	if !c.isV4(ctx) {
+		body := map[string]bool{
+			"developers_can_push":  opt.PushAccessLevel != nil && *opt.PushAccessLevel <= DeveloperAccess,
+			"developers_can_merge": opt.MergeAccessLevel != nil && *opt.MergeAccessLevel <= DeveloperAccess,
+		}
+
+		branch, e := doJSON[Branch](ctx, c, "PUT", branchPath(projectId, name)+"/protect", body, opts)
+		if e != nil {
+			err = e
+			return
+		}
+		protected = branch.protectedBranch()
+		return
+	}
+
+	req := c.newRequest(ctx, "POST", projectPath(projectId)+"/protected_branches", opts)
+	defer req.Close()
+
+	req.Param("name", name)
+	if opt.PushAccessLevel != nil {
+		req.Param("push_access_level", strconv.Itoa(int(*opt.PushAccessLevel)))
+	}
+	if opt.MergeAccessLevel != nil {
+		req.Param("merge_access_level", strconv.Itoa(int(*opt.MergeAccessLevel)))
+	}
+	if opt.UnprotectAccessLevel != nil {
+		req.Param("unprotect_access_level", strconv.Itoa(int(*opt.UnprotectAccessLevel)))
+	}
+	if opt.AllowForcePush {
+		req.Param("allow_force_push", "true")
+	}
+
+	protected = &ProtectedBranch{}
+	if _, err = req.Do(protected); err != nil {
+		protected = nil
+	}
+	return
+}
+
+//UnprotectBranch 取消分支保护
+func (c *Client) UnprotectBranch(ctx context.Context, projectId, name string, opts ...RequestOption) (err error) {
+	if !c.isV4(ctx) {
+		_, err = doJSON[Branch](ctx, c, "PUT", branchPath(projectId, name)+"/unprotect", nil, opts)
+		return
+	}
+
+	req := c.newRequest(ctx, "DELETE", projectPath(projectId)+"/protected_branches/"+url.PathEscape(name), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//ListProtectedBranches 列出项目的受保护分支，自动获取所有分页，只支持v4
+func (c *Client) ListProtectedBranches(ctx context.Context, projectId string, opt ListOptions, opts ...RequestOption) (protected []ProtectedBranch, err error) {
+	return listAll[ProtectedBranch](ctx, c, projectPath(projectId)+"/protected_branches", opt, nil, opts)
+}
+
+//v3的保护分支接口返回分支信息，转换为ProtectedBranch
+func (b *Branch) protectedBranch() *ProtectedBranch {
+	level := func(allowed bool) []BranchAccess {
+		if allowed {
+			return []BranchAccess{{AccessLevel: DeveloperAccess, AccessLevelDescription: "Developers + Maintainers"}}
+		}
+		return []BranchAccess{{AccessLevel: MaintainerAccess, AccessLevelDescription: "Maintainers"}}
+	}
+
+	return &ProtectedBranch{
+		Name:              b.Name,
+		PushAccessLevels:  level(b.DevelopersCanPush),
+		MergeAccessLevels: level(b.DevelopersCanMerge),
+	}
+}
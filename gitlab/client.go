@@ -0,0 +1,225 @@
+package gitlab
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"config"
+)
+
+//Client 保存访问一个Gitlab实例所需的全部信息，不同的Client可以同时访问不同的Gitlab或使用不同的Token
+type Client struct {
+	BaseURL     string        //Gitlab API地址，例如 http://example.com/api/
+	APIVersion  string        //API版本，v3、v4，为空或auto时自动检测
+	Token       string        //请求时使用的PRIVATE-TOKEN，Auth不为nil时忽略
+	Auth        Authenticator //认证方式，为nil时使用Token
+	NamespaceId string        //CreateProject 创建项目时使用的namespace
+	HTTPClient  *http.Client  //为nil时使用默认设置，可以通过HTTPClient.Transport注入自定义的http.RoundTripper
+	Headers     http.Header   //每个请求都会附带的Header
+	PerPage     int           //自动获取所有分页时每页的数量，为0时使用100
+	Retry       *RetryPolicy  //失败重试策略，为nil时不重试
+	RateLimiter Limiter       //客户端限流，为nil时不限流
+	Hooks       []Hook        //请求和返回的钩子，用于日志、监控等
+
+	rateLimit       rateLimitState
+	versionMu       sync.Mutex
+	detectedVersion string
+}
+
+//defaultTransport 未设置HTTPClient.Transport时使用，连接超时和等待返回的超时均为60秒
+var defaultTransport http.RoundTripper = &http.Transport{
+	Proxy:                 http.ProxyFromEnvironment,
+	DialContext:           (&net.Dialer{Timeout: 60 * time.Second}).DialContext,
+	ResponseHeaderTimeout: 60 * time.Second,
+	MaxIdleConnsPerHost:   100,
+}
+
+//DefaultClient 为包级函数使用的Client，为nil时根据config中的配置构建
+var DefaultClient *Client
+
+//NewClient 创建一个新的Client
+func NewClient(baseURL, apiVersion, token string) *Client {
+	return &Client{
+		BaseURL:    baseURL,
+		APIVersion: apiVersion,
+		Token:      token,
+		Headers:    make(http.Header),
+		Retry:      DefaultRetryPolicy(),
+	}
+}
+
+//根据config构建的Client，config中的配置变化时重新构建
+var (
+	configClientMu sync.Mutex
+	configClient   *Client
+	clientConfig   [4]string
+)
+
+//Default 返回包级函数使用的Client，未设置DefaultClient时使用根据config构建的Client，
+//该Client会被缓存以共享限流状态和检测到的API版本，config中的配置变化时重新构建
+func Default() *Client {
+	if DefaultClient != nil {
+		return DefaultClient
+	}
+
+	current := [4]string{config.GitUrl, config.APIVersion, config.AdminToken, config.NamespaceId}
+
+	configClientMu.Lock()
+	defer configClientMu.Unlock()
+
+	if configClient == nil || clientConfig != current {
+		configClient = NewClient(config.GitUrl, config.APIVersion, config.AdminToken)
+		configClient.NamespaceId = config.NamespaceId
+		clientConfig = current
+	}
+	return configClient
+}
+
+//自动分页时每页的数量
+func (c *Client) perPage() int {
+	if c.PerPage > 0 {
+		return c.PerPage
+	}
+	return defaultPerPage
+}
+
+//Client默认的认证方式
+func (c *Client) authenticator() Authenticator {
+	if c.Auth != nil {
+		return c.Auth
+	}
+	return PrivateToken(c.Token)
+}
+
+//发送请求使用的http.Client，在Transport之上增加限流和重试，超时由ctx控制
+func (c *Client) httpClient() *http.Client {
+	hc := &http.Client{
+		Transport: &retryTransport{client: c, base: c.transport()},
+	}
+	if c.HTTPClient != nil {
+		hc.CheckRedirect = c.HTTPClient.CheckRedirect
+		hc.Jar = c.HTTPClient.Jar
+	}
+	return hc
+}
+
+//实际发送请求的Transport
+func (c *Client) transport() http.RoundTripper {
+	if c.HTTPClient != nil && c.HTTPClient.Transport != nil {
+		return c.HTTPClient.Transport
+	}
+	return defaultTransport
+}
+
+//发送请求前等待客户端限流器和Gitlab的RateLimit配额
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return c.rateLimit.wait(ctx)
+}
+
+//项目接口的路径，projectId可以是数字ID或"namespace/project"，后者会被编码为namespace%2Fproject
+func projectPath(projectId string) string {
+	if strings.Contains(projectId, "/") {
+		projectId = url.PathEscape(projectId)
+	}
+	return "/projects/" + projectId
+}
+
+//分支接口的路径，分支名中的"/"需要编码
+func branchPath(projectId, branchName string) string {
+	return projectPath(projectId) + "/repository/branches/" + url.PathEscape(branchName)
+}
+
+//group接口的路径，groupId可以是数字ID或group的完整路径
+func groupPath(groupId string) string {
+	if strings.Contains(groupId, "/") {
+		groupId = url.PathEscape(groupId)
+	}
+	return "/groups/" + groupId
+}
+
+//拼接API的完整地址，path以"/"开头
+func (c *Client) endpoint(apiVersion, path string) string {
+	return c.BaseURL + apiVersion + path
+}
+
+//创建带有Token和默认Header的请求，请求绑定ctx，使用完毕后需要调用Close
+func (c *Client) newRequest(ctx context.Context, method, path string, opts []RequestOption) *request {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	apiVersion, err := c.version(ctx)
+	req := c.newVersionRequest(ctx, apiVersion, method, path, opts)
+	if err != nil {
+		//API版本检测失败时不发送请求，Response/Do/Stream直接返回检测的错误
+		req.err = err
+	}
+	return req
+}
+
+//创建指定API版本的请求
+func (c *Client) newVersionRequest(ctx context.Context, apiVersion, method, path string, opts []RequestOption) *request {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var o requestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	timeout := o.timeout
+	if timeout == 0 && c.HTTPClient != nil {
+		timeout = c.HTTPClient.Timeout
+	}
+
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	if o.retry {
+		ctx = context.WithValue(ctx, retryKey{}, true)
+	}
+
+	req := &request{
+		client: c,
+		ctx:    ctx,
+		cancel: cancel,
+		method: method,
+		url:    c.endpoint(apiVersion, path),
+		header: make(http.Header),
+		query:  make(url.Values),
+		params: make(url.Values),
+	}
+
+	req.auth = o.auth
+	if req.auth == nil {
+		req.auth = c.authenticator()
+	}
+
+	req.Header("Content-Type", "application/json")
+
+	for key, values := range c.Headers {
+		for _, value := range values {
+			req.header.Add(key, value)
+		}
+	}
+
+	if o.sudo != "" {
+		req.Header("Sudo", o.sudo)
+	}
+
+	return req
+}
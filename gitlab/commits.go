@@ -0,0 +1,310 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"time"
+
+	"util"
+)
+
+//Commit 提交的完整信息
+type Commit struct {
+	Id             string       `json:"id"`
+	ShortId        string       `json:"short_id"`
+	Title          string       `json:"title"`
+	Message        string       `json:"message"`
+	AuthorName     string       `json:"author_name"`
+	AuthorEmail    string       `json:"author_email"`
+	AuthoredDate   *time.Time   `json:"authored_date"`
+	CommitterName  string       `json:"committer_name"`
+	CommitterEmail string       `json:"committer_email"`
+	CommittedDate  *time.Time   `json:"committed_date"`
+	CreatedAt      *time.Time   `json:"created_at"`
+	ParentIds      []string     `json:"parent_ids"`
+	WebUrl         string       `json:"web_url"`
+	Stats          *CommitStats `json:"stats"`  //GetCommit或ListCommits设置WithStats时返回
+	Status         string       `json:"status"` //最近一次pipeline的状态
+}
+
+//CommitStats 提交修改的行数
+type CommitStats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Total     int `json:"total"`
+}
+
+//Diff 单个文件的修改内容
+type Diff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	AMode       string `json:"a_mode"`
+	BMode       string `json:"b_mode"`
+	Diff        string `json:"diff"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+}
+
+//Compare 两个ref的比较结果
+type Compare struct {
+	Commit         *Commit  `json:"commit"`  //to对应的提交
+	Commits        []Commit `json:"commits"` //from之后to包含的提交，按时间正序
+	Diffs          []Diff   `json:"diffs"`
+	CompareTimeout bool     `json:"compare_timeout"`
+	CompareSameRef bool     `json:"compare_same_ref"`
+}
+
+//提交接口的路径，sha也可以是分支名或tag
+func commitPath(projectId, sha string) string {
+	return projectPath(projectId) + "/repository/commits/" + url.PathEscape(sha)
+}
+
+//ListCommitsOptions 提交列表的过滤条件
+type ListCommitsOptions struct {
+	ListOptions
+	RefName     string     //分支名、tag或commit id，为空时使用默认分支
+	Path        string     //只返回修改过该文件或目录的提交
+	Since       *time.Time //只返回该时间之后的提交
+	Until       *time.Time //只返回该时间之前的提交
+	Author      string     //按作者过滤，只支持v4
+	All         bool       //返回所有分支的提交
+	WithStats   bool       //返回每个提交修改的行数
+	FirstParent bool       //合并提交只沿第一个父提交查找
+}
+
+func (o ListCommitsOptions) setParams(req *request) {
+	for key, value := range map[string]string{
+		"ref_name": o.RefName,
+		"path":     o.Path,
+		"author":   o.Author,
+	} {
+		if value != "" {
+			req.Param(key, value)
+		}
+	}
+
+	if o.Since != nil {
+		req.Param("since", o.Since.Format(time.RFC3339))
+	}
+	if o.Until != nil {
+		req.Param("until", o.Until.Format(time.RFC3339))
+	}
+
+	for key, value := range map[string]bool{
+		"all":          o.All,
+		"with_stats":   o.WithStats,
+		"first_parent": o.FirstParent,
+	} {
+		if value {
+			req.Param(key, "true")
+		}
+	}
+}
+
+//ListCommits 列出项目的提交，自动获取所有分页，历史较长时建议使用CommitIterator
+func (c *Client) ListCommits(ctx context.Context, projectId string, opt ListCommitsOptions, opts ...RequestOption) (commits []Commit, err error) {
+	return listAll[Commit](ctx, c, projectPath(projectId)+"/repository/commits", opt.ListOptions, opt.setParams, opts)
+}
+
+//ListCommitsPage 获取提交列表的一页数据
+func (c *Client) ListCommitsPage(ctx context.Context, projectId string, opt ListCommitsOptions, opts ...RequestOption) (commits []Commit, pageInfo *PageInfo, err error) {
+	return listPage[Commit](ctx, c, projectPath(projectId)+"/repository/commits", opt.ListOptions, opt.setParams, opts)
+}
+
+//CommitIterator 逐页遍历项目的提交
+func (c *Client) CommitIterator(ctx context.Context, projectId string, opt ListCommitsOptions, opts ...RequestOption) *Iterator[Commit] {
+	return listIterator[Commit](ctx, c, projectPath(projectId)+"/repository/commits", opt.ListOptions, opt.setParams, opts)
+}
+
+//GetCommit 获取单个提交，包含修改的行数
+func (c *Client) GetCommit(ctx context.Context, projectId, sha string, opts ...RequestOption) (commit *Commit, err error) {
+	req := c.newRequest(ctx, "GET", commitPath(projectId, sha), opts)
+	defer req.Close()
+
+	req.Param("stats", "true")
+
+	commit = &Commit{}
+	if _, err = req.Do(commit); err != nil {
+		commit = nil
+	}
+	return
+}
+
+//GetCommitDiff 获取提交中每个文件的修改内容，自动获取所有分页
+func (c *Client) GetCommitDiff(ctx context.Context, projectId, sha string, opts ...RequestOption) (diffs []Diff, err error) {
+	return listAll[Diff](ctx, c, commitPath(projectId, sha)+"/diff", ListOptions{}, nil, opts)
+}
+
+//CompareRefs 比较两个分支、tag或commit，返回from之后to新增的提交和文件修改
+func (c *Client) CompareRefs(ctx context.Context, projectId, from, to string, opts ...RequestOption) (compare *Compare, err error) {
+	req := c.newRequest(ctx, "GET", projectPath(projectId)+"/repository/compare", opts)
+	defer req.Close()
+
+	req.Param("from", from)
+	req.Param("to", to)
+
+	compare = &Compare{}
+	if _, err = req.Do(compare); err != nil {
+		compare = nil
+	}
+	return
+}
+
+//CherryPickCommit 将提交cherry-pick到branch，返回新的提交，只支持v4
+func (c *Client) CherryPickCommit(ctx context.Context, projectId, sha, branch string, opts ...RequestOption) (commit *Commit, err error) {
+	return doJSON[Commit](ctx, c, "POST", commitPath(projectId, sha)+"/cherry_pick", map[string]string{"branch": branch}, opts)
+}
+
+//RevertCommit 在branch上创建撤销该提交的新提交，只支持v4
+func (c *Client) RevertCommit(ctx context.Context, projectId, sha, branch string, opts ...RequestOption) (commit *Commit, err error) {
+	return doJSON[Commit](ctx, c, "POST", commitPath(projectId, sha)+"/revert", map[string]string{"branch": branch}, opts)
+}
+
+//CommitAction 一次提交中对单个文件的操作
+type CommitAction struct {
+	Action          string `json:"action"` //create、update、delete、move、chmod
+	FilePath        string `json:"file_path"`
+	PreviousPath    string `json:"previous_path,omitempty"` //move时的原路径
+	Content         string `json:"content,omitempty"`
+	Encoding        string `json:"encoding,omitempty"`       //text或base64
+	LastCommitId    string `json:"last_commit_id,omitempty"` //文件在此之后被修改过时提交失败
+	ExecuteFilemode *bool  `json:"execute_filemode,omitempty"`
+}
+
+/*
+CommitBuilder 将多个文件的创建、更新、删除、移动和权限修改合并为一次提交，
+所有操作要么全部成功要么全部失败，不会出现分支只更新了一部分的情况
+用法：
+	commit, err := client.NewCommit(projectId, "master", "publish configs").
+		Create("conf/a.yml", a).
+		Update("conf/b.yml", b).LastCommitId(lastId).
+		Delete("conf/old.yml").
+		Commit(ctx)
+*/
+type CommitBuilder struct {
+	client      *Client
+	projectId   string
+	branch      string
+	message     string
+	startBranch string
+	authorName  string
+	authorEmail string
+	force       bool
+	actions     []CommitAction
+}
+
+//NewCommit 创建提交到branch的CommitBuilder
+func (c *Client) NewCommit(projectId, branch, message string) *CommitBuilder {
+	return &CommitBuilder{
+		client:    c,
+		projectId: projectId,
+		branch:    branch,
+		message:   message,
+	}
+}
+
+//StartBranch branch不存在时从startBranch创建
+func (b *CommitBuilder) StartBranch(startBranch string) *CommitBuilder {
+	b.startBranch = startBranch
+	return b
+}
+
+//Author 指定提交的作者，默认为Token对应的用户
+func (b *CommitBuilder) Author(name, email string) *CommitBuilder {
+	b.authorName = name
+	b.authorEmail = email
+	return b
+}
+
+//Force 使用startBranch的内容覆盖branch，只支持v4
+func (b *CommitBuilder) Force(force bool) *CommitBuilder {
+	b.force = force
+	return b
+}
+
+//Action 添加自定义的文件操作
+func (b *CommitBuilder) Action(action CommitAction) *CommitBuilder {
+	b.actions = append(b.actions, action)
+	return b
+}
+
+//Create 创建文本文件
+func (b *CommitBuilder) Create(filepath, content string) *CommitBuilder {
+	return b.Action(CommitAction{Action: "create", FilePath: filepath, Content: content, Encoding: "text"})
+}
+
+//CreateBinary 创建二进制文件，内容以base64提交
+func (b *CommitBuilder) CreateBinary(filepath string, content []byte) *CommitBuilder {
+	return b.Action(CommitAction{Action: "create", FilePath: filepath, Content: base64.StdEncoding.EncodeToString(content), Encoding: "base64"})
+}
+
+//Update 更新文本文件
+func (b *CommitBuilder) Update(filepath, content string) *CommitBuilder {
+	return b.Action(CommitAction{Action: "update", FilePath: filepath, Content: content, Encoding: "text"})
+}
+
+//UpdateBinary 更新二进制文件，内容以base64提交
+func (b *CommitBuilder) UpdateBinary(filepath string, content []byte) *CommitBuilder {
+	return b.Action(CommitAction{Action: "update", FilePath: filepath, Content: base64.StdEncoding.EncodeToString(content), Encoding: "base64"})
+}
+
+//Delete 删除文件
+func (b *CommitBuilder) Delete(filepath string) *CommitBuilder {
+	return b.Action(CommitAction{Action: "delete", FilePath: filepath})
+}
+
+//Move 移动文件，内容保持不变
+func (b *CommitBuilder) Move(previousPath, filepath string) *CommitBuilder {
+	return b.Action(CommitAction{Action: "move", FilePath: filepath, PreviousPath: previousPath})
+}
+
+//Chmod 修改文件的可执行权限，只支持v4
+func (b *CommitBuilder) Chmod(filepath string, executable bool) *CommitBuilder {
+	return b.Action(CommitAction{Action: "chmod", FilePath: filepath, ExecuteFilemode: &executable})
+}
+
+//LastCommitId 为最近添加的操作设置last_commit_id，文件在该提交之后被修改过时整个提交失败
+func (b *CommitBuilder) LastCommitId(commitId string) *CommitBuilder {
+	if len(b.actions) > 0 {
+		b.actions[len(b.actions)-1].LastCommitId = commitId
+	}
+	return b
+}
+
+//Len 已添加的操作数量
+func (b *CommitBuilder) Len() int {
+	return len(b.actions)
+}
+
+//Commit 将所有操作作为一次提交发送，返回新的提交
+func (b *CommitBuilder) Commit(ctx context.Context, opts ...RequestOption) (commit *Commit, err error) {
+	if len(b.actions) == 0 {
+		err = util.NewError("commit has no actions")
+		return
+	}
+
+	c := b.client
+	v4 := c.isV4(ctx)
+
+	body := map[string]interface{}{
+		branchParam(v4):  b.branch,
+		"commit_message": b.message,
+		"actions":        b.actions,
+	}
+	if b.startBranch != "" {
+		body["start_branch"] = b.startBranch
+	}
+	if b.authorName != "" {
+		body["author_name"] = b.authorName
+	}
+	if b.authorEmail != "" {
+		body["author_email"] = b.authorEmail
+	}
+	if b.force {
+		body["force"] = true
+	}
+
+	return doJSON[Commit](ctx, c, "POST", projectPath(b.projectId)+"/repository/commits", body, opts)
+}
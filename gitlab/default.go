@@ -0,0 +1,149 @@
+package gitlab
+
+import (
+	"context"
+)
+
+/*
+包级函数保持原有的调用方式，统一通过Default()返回的Client访问Gitlab
+*/
+
+//通过AdminToken获取当前用户username的信息,暂时没有管理员权限，无法使用
+func GitUserAuth(username string) (user User, err error) {
+	return Default().GitUserAuth(username)
+}
+
+//GitUserAuthContext 同GitUserAuth，请求受ctx控制
+func GitUserAuthContext(ctx context.Context, username string, opts ...RequestOption) (user User, err error) {
+	return Default().GitUserAuthContext(ctx, username, opts...)
+}
+
+//创建一个新的Project，统一创建在config.NamespaceId指定的namespace下面
+func CreateProject(projectName string) (statusCode int, err error) {
+	return Default().CreateProject(projectName)
+}
+
+//CreateProjectContext 同CreateProject，请求受ctx控制
+func CreateProjectContext(ctx context.Context, projectName string, opts ...RequestOption) (statusCode int, err error) {
+	return Default().CreateProjectContext(ctx, projectName, opts...)
+}
+
+//更新项目的名称和Path
+func UpdateProject(projectId, newProjectName string) (statusCode int, err error) {
+	return Default().UpdateProject(projectId, newProjectName)
+}
+
+//UpdateProjectContext 同UpdateProject，请求受ctx控制
+func UpdateProjectContext(ctx context.Context, projectId, newProjectName string, opts ...RequestOption) (statusCode int, err error) {
+	return Default().UpdateProjectContext(ctx, projectId, newProjectName, opts...)
+}
+
+//通过项目的namespace和name查询项目信息
+func SearchProjectByName(namespace, projectName string) (projectInfo ProjectInfo, err error) {
+	return Default().SearchProjectByName(namespace, projectName)
+}
+
+//SearchProjectByNameContext 同SearchProjectByName，请求受ctx控制
+func SearchProjectByNameContext(ctx context.Context, namespace, projectName string, opts ...RequestOption) (projectInfo ProjectInfo, err error) {
+	return Default().SearchProjectByNameContext(ctx, namespace, projectName, opts...)
+}
+
+//通过项目ID查询项目信息
+func SearchProjectById(projectId string) (projectInfo ProjectInfo, err error) {
+	return Default().SearchProjectById(projectId)
+}
+
+//SearchProjectByIdContext 同SearchProjectById，请求受ctx控制
+func SearchProjectByIdContext(ctx context.Context, projectId string, opts ...RequestOption) (projectInfo ProjectInfo, err error) {
+	return Default().SearchProjectByIdContext(ctx, projectId, opts...)
+}
+
+//通过项目名称获取branch的信息
+func ListProjectBranchInfoByName(namespace, projectName, branchName string) (projectBranchInfo *ProjectBranchInfo, err error) {
+	return Default().ListProjectBranchInfoByName(namespace, projectName, branchName)
+}
+
+//ListProjectBranchInfoByNameContext 同ListProjectBranchInfoByName，请求受ctx控制
+func ListProjectBranchInfoByNameContext(ctx context.Context, namespace, projectName, branchName string, opts ...RequestOption) (projectBranchInfo *ProjectBranchInfo, err error) {
+	return Default().ListProjectBranchInfoByNameContext(ctx, namespace, projectName, branchName, opts...)
+}
+
+//通过项目ID获取branch的信息
+func ListProjectBranchInfoById(projectId, branchName string) (projectBranchInfo *ProjectBranchInfo, err error) {
+	return Default().ListProjectBranchInfoById(projectId, branchName)
+}
+
+//ListProjectBranchInfoByIdContext 同ListProjectBranchInfoById，请求受ctx控制
+func ListProjectBranchInfoByIdContext(ctx context.Context, projectId, branchName string, opts ...RequestOption) (projectBranchInfo *ProjectBranchInfo, err error) {
+	return Default().ListProjectBranchInfoByIdContext(ctx, projectId, branchName, opts...)
+}
+
+//获取文件的最新内容,包括content和commit_id等
+func GetFileContentRepo(projectId, branchName, filepath string) (repoFile RepoFile, err error) {
+	return Default().GetFileContentRepo(projectId, branchName, filepath)
+}
+
+//GetFileContentRepoContext 同GetFileContentRepo，请求受ctx控制
+func GetFileContentRepoContext(ctx context.Context, projectId, branchName, filepath string, opts ...RequestOption) (repoFile RepoFile, err error) {
+	return Default().GetFileContentRepoContext(ctx, projectId, branchName, filepath, opts...)
+}
+
+//在项目中创建新的文件
+func CreateNewFileRepo(projectId, branchName, filepath, content, commitMsg string) (repoUpdateFile RepoUpdateFile, err error) {
+	return Default().CreateNewFileRepo(projectId, branchName, filepath, content, commitMsg)
+}
+
+//CreateNewFileRepoContext 同CreateNewFileRepo，请求受ctx控制
+func CreateNewFileRepoContext(ctx context.Context, projectId, branchName, filepath, content, commitMsg string, opts ...RequestOption) (repoUpdateFile RepoUpdateFile, err error) {
+	return Default().CreateNewFileRepoContext(ctx, projectId, branchName, filepath, content, commitMsg, opts...)
+}
+
+//更新项目中文件的内容
+func UpdateExistFileRepo(projectId, branchName, filepath, content, commitMsg string) (repoUpdateFile RepoUpdateFile, err error) {
+	return Default().UpdateExistFileRepo(projectId, branchName, filepath, content, commitMsg)
+}
+
+//UpdateExistFileRepoContext 同UpdateExistFileRepo，请求受ctx控制
+func UpdateExistFileRepoContext(ctx context.Context, projectId, branchName, filepath, content, commitMsg string, opts ...RequestOption) (repoUpdateFile RepoUpdateFile, err error) {
+	return Default().UpdateExistFileRepoContext(ctx, projectId, branchName, filepath, content, commitMsg, opts...)
+}
+
+//删除项目中已存在的文件
+func DeleteExistFileRepo(projectId, branchName, filepath, commitMsg string) (repoUpdateFile RepoUpdateFile, err error) {
+	return Default().DeleteExistFileRepo(projectId, branchName, filepath, commitMsg)
+}
+
+//DeleteExistFileRepoContext 同DeleteExistFileRepo，请求受ctx控制
+func DeleteExistFileRepoContext(ctx context.Context, projectId, branchName, filepath, commitMsg string, opts ...RequestOption) (repoUpdateFile RepoUpdateFile, err error) {
+	return Default().DeleteExistFileRepoContext(ctx, projectId, branchName, filepath, commitMsg, opts...)
+}
+
+//根据子目录获取该目录下的文件或子目录信息，不会自动递归子目录查询，自动获取所有分页
+func ListRepoTreeByDirectory(projectId, branchName, filepath string) (repoTrees []RepoTree, err error) {
+	return Default().ListRepoTreeByDirectory(projectId, branchName, filepath)
+}
+
+//ListRepoTreeByDirectoryContext 同ListRepoTreeByDirectory，请求受ctx控制
+func ListRepoTreeByDirectoryContext(ctx context.Context, projectId, branchName, filepath string, opts ...RequestOption) (repoTrees []RepoTree, err error) {
+	return Default().ListRepoTreeByDirectoryContext(ctx, projectId, branchName, filepath, opts...)
+}
+
+//获取项目根目录下的所有子目录和文件信息，不会递归查询，自动获取所有分页
+func ListRepoTree(projectId, branchName string) (repoTrees []RepoTree, err error) {
+	return Default().ListRepoTree(projectId, branchName)
+}
+
+//ListRepoTreeContext 同ListRepoTree，请求受ctx控制
+func ListRepoTreeContext(ctx context.Context, projectId, branchName string, opts ...RequestOption) (repoTrees []RepoTree, err error) {
+	return Default().ListRepoTreeContext(ctx, projectId, branchName, opts...)
+}
+
+//根据commitid获取文件的内容
+func GetFileContentByCommitid(projectId, sha, filepath string) (content string, err error) {
+	return Default().GetFileContentByCommitid(projectId, sha, filepath)
+}
+
+//GetFileContentByCommitidContext 同GetFileContentByCommitid，请求受ctx控制
+func GetFileContentByCommitidContext(ctx context.Context, projectId, sha, filepath string, opts ...RequestOption) (content string, err error) {
+	return Default().GetFileContentByCommitidContext(ctx, projectId, sha, filepath, opts...)
+}
@@ -0,0 +1,194 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+//APIError Gitlab返回非2xx状态码时的错误，保留了请求信息和Gitlab给出的错误原因
+type APIError struct {
+	Method       string              //请求方法
+	URL          string              //请求地址
+	StatusCode   int                 //HTTP状态码
+	Status       string              //HTTP状态，如 "404 Not Found"
+	Message      string              //返回内容中字符串形式的message字段
+	ErrorMessage string              //返回内容中的error字段
+	Errors       map[string][]string //参数校验失败时message为对象，按字段保存错误信息
+	Body         []byte              //原始的返回内容
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("Gitlab API Error, %s %s Status:%s", e.Method, e.URL, e.Status)
+
+	if reason := e.Reason(); reason != "" {
+		msg += ", Message:" + reason
+	}
+	return msg
+}
+
+//Reason 返回Gitlab给出的错误原因，依次取message、error和校验错误
+func (e *APIError) Reason() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.ErrorMessage != "" {
+		return e.ErrorMessage
+	}
+	if len(e.Errors) == 0 {
+		return ""
+	}
+
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	reasons := make([]string, 0, len(fields))
+	for _, field := range fields {
+		reasons = append(reasons, field+" "+strings.Join(e.Errors[field], ", "))
+	}
+	return strings.Join(reasons, "; ")
+}
+
+//根据返回内容构建APIError，返回内容不是JSON时只保留原始内容
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       body,
+	}
+
+	if req != nil {
+		e.Method = req.Method
+		if req.URL != nil {
+			e.URL = req.URL.String()
+		}
+	}
+
+	var data struct {
+		Message json.RawMessage `json:"message"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(body, &data) != nil {
+		return e
+	}
+
+	e.Message, e.Errors = parseErrorMessage(data.Message)
+
+	var errorText string
+	if json.Unmarshal(data.Error, &errorText) == nil {
+		e.ErrorMessage = errorText
+	}
+	return e
+}
+
+/*
+message字段有多种形式：
+"404 Project Not Found"
+{"name": ["has already been taken"]}
+["branch is protected"]
+*/
+func parseErrorMessage(raw json.RawMessage) (message string, fieldErrors map[string][]string) {
+	if len(raw) == 0 {
+		return
+	}
+
+	if json.Unmarshal(raw, &message) == nil {
+		return
+	}
+
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		message = strings.Join(list, ", ")
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(raw, &fields) != nil {
+		return
+	}
+
+	fieldErrors = make(map[string][]string, len(fields))
+	for field, value := range fields {
+		var text string
+		if json.Unmarshal(value, &text) == nil {
+			fieldErrors[field] = []string{text}
+			continue
+		}
+
+		var texts []string
+		if json.Unmarshal(value, &texts) == nil {
+			fieldErrors[field] = texts
+		}
+	}
+	return
+}
+
+//FileConflictError 文件在读取之后被修改、删除或创建，当前值为空表示文件已不存在
+type FileConflictError struct {
+	FilePath         string
+	Branch           string
+	ExpectedBlobId   string
+	ExpectedCommitId string
+	CurrentBlobId    string
+	CurrentCommitId  string
+	Err              error //由Gitlab检测到冲突时为对应的APIError
+}
+
+func (e *FileConflictError) Error() string {
+	msg := fmt.Sprintf("file %s on branch %s has changed since it was read", e.FilePath, e.Branch)
+
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	if e.CurrentBlobId == "" {
+		return msg + ", current: not exists"
+	}
+	return msg + fmt.Sprintf(", current blob_id:%s last_commit_id:%s", e.CurrentBlobId, e.CurrentCommitId)
+}
+
+func (e *FileConflictError) Unwrap() error {
+	return e.Err
+}
+
+//IsFileConflict 文件在读取之后被其他人修改，需要重新读取后再提交
+func IsFileConflict(err error) bool {
+	var conflict *FileConflictError
+	return errors.As(err, &conflict)
+}
+
+//判断err是否为指定状态码的APIError
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+//IsNotFound 项目、分支或文件不存在
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+//IsConflict 资源冲突，如分支或项目已存在
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+//IsForbidden Token没有对应的权限
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+//IsUnauthorized Token无效或已过期
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+//IsBadRequest 参数错误，如文件已存在时再次创建
+func IsBadRequest(err error) bool {
+	return hasStatus(err, http.StatusBadRequest)
+}
@@ -0,0 +1,230 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//Bytes 返回解码后的文件内容
+func (f *RepoFile) Bytes() ([]byte, error) {
+	if f.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(f.Content)
+	}
+	return []byte(f.Content), nil
+}
+
+//Text 以字符串返回解码后的文件内容
+func (f *RepoFile) Text() (string, error) {
+	data, err := f.Bytes()
+	return string(data), err
+}
+
+/*
+GetRawFile 以流的方式获取文件的原始内容，适合读取大文件，使用完毕后必须关闭返回的io.ReadCloser
+ref可以是分支名、tag或commit id
+*/
+func (c *Client) GetRawFile(ctx context.Context, projectId, ref, filepath string, opts ...RequestOption) (content io.ReadCloser, err error) {
+	v4 := c.isV4(ctx)
+
+	var req *request
+	if v4 {
+		req = c.newRequest(ctx, "GET", projectPath(projectId)+filesPath(filepath, v4)+"/raw", opts)
+		req.Param("ref", ref)
+	} else {
+		req = c.newRequest(ctx, "GET", projectPath(projectId)+"/repository/blobs/"+ref, opts)
+		req.Param("filepath", filepath)
+	}
+
+	content, _, err = req.Stream()
+	return
+}
+
+//FileOptions 创建或更新单个文件的参数
+type FileOptions struct {
+	Branch          string
+	CommitMessage   string
+	Content         []byte
+	Encoding        string //text或base64，为空时内容不是合法的UTF-8文本则使用base64
+	StartBranch     string //Branch不存在时从StartBranch创建，只支持v4
+	AuthorName      string
+	AuthorEmail     string
+	LastCommitId    string //更新时文件在此之后被修改过则失败，只支持v4
+	ExecuteFilemode *bool  //只支持v4
+}
+
+//内容包含NUL或不是合法的UTF-8时视为二进制文件
+func isBinary(content []byte) bool {
+	for _, b := range content {
+		if b == 0 {
+			return true
+		}
+	}
+	return !utf8.Valid(content)
+}
+
+func (o FileOptions) setParams(req *request, v4 bool) {
+	content, encoding := o.encode()
+	req.Param("content", content)
+	req.Param("encoding", encoding)
+
+	req.Param(branchParam(v4), o.Branch)
+	req.Param("commit_message", o.CommitMessage)
+
+	for key, value := range map[string]string{
+		"start_branch":   o.StartBranch,
+		"author_name":    o.AuthorName,
+		"author_email":   o.AuthorEmail,
+		"last_commit_id": o.LastCommitId,
+	} {
+		if value != "" {
+			req.Param(key, value)
+		}
+	}
+	if o.ExecuteFilemode != nil {
+		req.Param("execute_filemode", strconv.FormatBool(*o.ExecuteFilemode))
+	}
+}
+
+//CreateFile 创建文件，支持二进制内容
+func (c *Client) CreateFile(ctx context.Context, projectId, filepath string, opt FileOptions, opts ...RequestOption) (repoUpdateFile RepoUpdateFile, err error) {
+	return c.writeFile(ctx, "POST", projectId, filepath, opt, opts)
+}
+
+//UpdateFile 更新文件，支持二进制内容
+func (c *Client) UpdateFile(ctx context.Context, projectId, filepath string, opt FileOptions, opts ...RequestOption) (repoUpdateFile RepoUpdateFile, err error) {
+	return c.writeFile(ctx, "PUT", projectId, filepath, opt, opts)
+}
+
+func (c *Client) writeFile(ctx context.Context, method, projectId, filepath string, opt FileOptions, opts []RequestOption) (repoUpdateFile RepoUpdateFile, err error) {
+	v4 := c.isV4(ctx)
+
+	req := c.newRequest(ctx, method, projectPath(projectId)+filesPath(filepath, v4), opts)
+	defer req.Close()
+
+	if !v4 {
+		req.Param("file_path", filepath)
+	}
+	opt.setParams(req, v4)
+
+	if _, err = req.Do(&repoUpdateFile); err != nil {
+		return
+	}
+	repoUpdateFile.fill(filepath, opt.Branch)
+	return
+}
+
+/*
+UpsertFileOptions 创建或更新文件的参数
+FileOptions.LastCommitId作为读取文件时的last_commit_id，与ExpectedBlobId都为空时不检查文件是否被修改过，直接覆盖
+*/
+type UpsertFileOptions struct {
+	FileOptions
+	ExpectedBlobId string //读取文件时的blob_id
+}
+
+//文件内容的编码，为空时根据内容自动选择text或base64
+func (o FileOptions) encode() (content, encoding string) {
+	encoding = o.Encoding
+	if encoding == "" {
+		encoding = "text"
+		if isBinary(o.Content) {
+			encoding = "base64"
+		}
+	}
+
+	if encoding == "base64" {
+		return base64.StdEncoding.EncodeToString(o.Content), encoding
+	}
+	return string(o.Content), encoding
+}
+
+/*
+UpsertFile 文件不存在时创建，存在时更新，返回新的提交
+设置了ExpectedBlobId或LastCommitId时，文件在读取之后被修改或删除、或者读取时不存在而现在已被创建，都返回*FileConflictError，
+更新时会带上last_commit_id，由Gitlab保证检查和提交之间文件没有被其他人修改；
+未设置期望值时，只有创建过程中文件被其他人创建才返回*FileConflictError
+用法：
+	file, _ := client.GetFileContentRepoContext(ctx, projectId, "master", "conf/a.yml")
+	commit, err := client.UpsertFile(ctx, projectId, "conf/a.yml", gitlab.UpsertFileOptions{
+		FileOptions:    gitlab.FileOptions{Branch: "master", CommitMessage: "update a.yml", Content: content},
+		ExpectedBlobId: file.BlobId,
+	})
+	if gitlab.IsFileConflict(err) {
+	}
+*/
+func (c *Client) UpsertFile(ctx context.Context, projectId, filepath string, opt UpsertFileOptions, opts ...RequestOption) (commit *Commit, err error) {
+	conflict := &FileConflictError{
+		FilePath:         filepath,
+		Branch:           opt.Branch,
+		ExpectedBlobId:   opt.ExpectedBlobId,
+		ExpectedCommitId: opt.LastCommitId,
+	}
+	expected := opt.ExpectedBlobId != "" || opt.LastCommitId != ""
+
+	current, err := c.GetFileContentRepoContext(ctx, projectId, opt.Branch, filepath, opts...)
+	exists := err == nil
+	if err != nil && !IsNotFound(err) {
+		return
+	}
+	err = nil
+
+	content, encoding := opt.encode()
+	action := CommitAction{
+		Action:          "create",
+		FilePath:        filepath,
+		Content:         content,
+		Encoding:        encoding,
+		ExecuteFilemode: opt.ExecuteFilemode,
+	}
+
+	if exists {
+		conflict.CurrentBlobId = current.BlobId
+		conflict.CurrentCommitId = current.LastCommitId
+
+		if opt.ExpectedBlobId != "" && opt.ExpectedBlobId != current.BlobId ||
+			opt.LastCommitId != "" && opt.LastCommitId != current.LastCommitId {
+			err = conflict
+			return
+		}
+
+		action.Action = "update"
+		if expected {
+			action.LastCommitId = current.LastCommitId
+		}
+	} else if expected {
+		err = conflict
+		return
+	}
+
+	builder := c.NewCommit(projectId, opt.Branch, opt.CommitMessage).
+		StartBranch(opt.StartBranch).
+		Author(opt.AuthorName, opt.AuthorEmail).
+		Action(action)
+
+	commit, err = builder.Commit(ctx, opts...)
+	if err != nil && isFileChanged(err) {
+		conflict.Err = err
+		err = conflict
+	}
+	return
+}
+
+//Gitlab检查last_commit_id失败或创建时文件已存在
+func isFileChanged(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.StatusCode != http.StatusBadRequest && apiErr.StatusCode != http.StatusConflict {
+		return false
+	}
+
+	reason := strings.ToLower(apiErr.Reason())
+	return strings.Contains(reason, "changed since") || strings.Contains(reason, "already exists")
+}
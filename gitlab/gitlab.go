@@ -1,23 +1,12 @@
 package gitlab
 
 import (
-	//"encoding/json"
+	"context"
+	"path"
 
-	"util"
-	//"time"
-
-	"config"
-
-	"github.com/astaxie/beego/httplib"
 	"github.com/bitly/go-simplejson"
-	//"github.com/smallnest/goreq"
 )
 
-type ProjectInfo struct {
-	ProjectId    int    `json:"id"`
-	SshUrlToRepo string `json:"ssh_url_to_repo"`
-}
-
 type ProjectBranchInfo struct {
 	Name            string
 	CommitId        string
@@ -26,13 +15,16 @@ type ProjectBranchInfo struct {
 }
 
 type RepoFile struct {
-	FileName string `json:"file_name"`
-	FilePath string `json:"file_path"`
-	Size     int    `json:"size"`
-	Encoding string `json:"encoding"`
-	Content  string `json:"content"`
-	Ref      string `json:"ref"`
-	CommitId string `json:"commit_id"`
+	FileName      string `json:"file_name"`
+	FilePath      string `json:"file_path"`
+	Size          int    `json:"size"`
+	Encoding      string `json:"encoding"`
+	Content       string `json:"content"` //Encoding为base64时需要解码，使用Bytes获取原始内容
+	Ref           string `json:"ref"`
+	BlobId        string `json:"blob_id"`
+	CommitId      string `json:"commit_id"`
+	LastCommitId  string `json:"last_commit_id"`
+	ContentSha256 string `json:"content_sha256"`
 }
 
 type RepoTree struct {
@@ -40,6 +32,7 @@ type RepoTree struct {
 	Type string `json:"type"`
 	Mode string `json:"mode"`
 	Id   string `json:"id"`
+	Path string `json:"path"` //相对于仓库根目录的完整路径
 }
 
 type RepoUpdateFile struct {
@@ -47,6 +40,16 @@ type RepoUpdateFile struct {
 	BranchName string `json:"branch_name"`
 }
 
+//v4返回的分支字段为branch，统一使用请求参数补全
+func (f *RepoUpdateFile) fill(filepath, branchName string) {
+	if f.FilePath == "" {
+		f.FilePath = filepath
+	}
+	if f.BranchName == "" {
+		f.BranchName = branchName
+	}
+}
+
 type CommitInfo struct {
 	Id  string
 	Msg string
@@ -57,20 +60,28 @@ type User struct {
 	Username     string `json:"username"`
 	Email        string `json:"email"`
 	Name         string `json:"name"`
+	State        string `json:"state"`
+	AvatarUrl    string `json:"avatar_url"`
+	WebUrl       string `json:"web_url"`
 	PrivateToken string `json:"private_token"`
 }
 
 /*
-默认连接超时和读写超时都使用beego默认值60秒
+默认连接超时和等待返回的超时都是60秒
+ctx带有deadline、通过WithTimeout设置了超时或HTTPClient.Timeout不为0时，以其中最早的时间为准
 */
 
 //通过AdminToken获取当前用户username的信息,暂时没有管理员权限，无法使用
-func GitUserAuth(username string) (user User, err error) {
-	auth_url := config.GitUrl + config.APIVersion + "/user"
+func (c *Client) GitUserAuth(username string) (user User, err error) {
+	return c.GitUserAuthContext(context.Background(), username)
+}
+
+//GitUserAuthContext 同GitUserAuth，请求受ctx控制
+func (c *Client) GitUserAuthContext(ctx context.Context, username string, opts ...RequestOption) (user User, err error) {
+	auth_url := "/user"
 
-	req := httplib.Get(auth_url)
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+	req := c.newRequest(ctx, "GET", auth_url, opts)
+	defer req.Close()
 	req.Header("SUDO", username)
 
 	resp, err := req.Response()
@@ -82,22 +93,30 @@ func GitUserAuth(username string) (user User, err error) {
 	if resp.StatusCode == 200 {
 		err = req.ToJSON(&user)
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 	return
 }
 
-//创建一个新的Project，统一创建在[slnanal] namespace下面
-func CreateProject(projectName string) (statusCode int, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects"
+//创建一个新的Project，统一创建在c.NamespaceId指定的namespace下面
+func (c *Client) CreateProject(projectName string) (statusCode int, err error) {
+	return c.CreateProjectContext(context.Background(), projectName)
+}
+
+//CreateProjectContext 同CreateProject，请求受ctx控制
+func (c *Client) CreateProjectContext(ctx context.Context, projectName string, opts ...RequestOption) (statusCode int, err error) {
+	project_url := "/projects"
 
-	req := httplib.Post(project_url)
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+	req := c.newRequest(ctx, "POST", project_url, opts)
+	defer req.Close()
 
 	req.Param("name", projectName)
-	req.Param("namespace_id", config.NamespaceId)
-	req.Param("public", "false")
+	req.Param("namespace_id", c.NamespaceId)
+	if c.isV4(ctx) {
+		req.Param("visibility", "private")
+	} else {
+		req.Param("public", "false")
+	}
 
 	resp, err := req.Response()
 
@@ -108,18 +127,22 @@ func CreateProject(projectName string) (statusCode int, err error) {
 	if resp.StatusCode == 200 || resp.StatusCode == 201 {
 		statusCode = resp.StatusCode
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 	return
 }
 
 //更新项目的名称和Path
-func UpdateProject(projectId, newProjectName string) (statusCode int, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + projectId
+func (c *Client) UpdateProject(projectId, newProjectName string) (statusCode int, err error) {
+	return c.UpdateProjectContext(context.Background(), projectId, newProjectName)
+}
 
-	req := httplib.Put(project_url)
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+//UpdateProjectContext 同UpdateProject，请求受ctx控制
+func (c *Client) UpdateProjectContext(ctx context.Context, projectId, newProjectName string, opts ...RequestOption) (statusCode int, err error) {
+	project_url := "/projects/" + projectId
+
+	req := c.newRequest(ctx, "PUT", project_url, opts)
+	defer req.Close()
 
 	req.Param("name", newProjectName)
 	req.Param("path", newProjectName)
@@ -133,19 +156,22 @@ func UpdateProject(projectId, newProjectName string) (statusCode int, err error)
 	if resp.StatusCode == 200 {
 		statusCode = resp.StatusCode
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 	return
 }
 
 //通过项目的namespace和name查询项目信息
-func SearchProjectByName(namespace, projectName string) (projectInfo ProjectInfo, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + namespace + "%2F" + projectName
+func (c *Client) SearchProjectByName(namespace, projectName string) (projectInfo ProjectInfo, err error) {
+	return c.SearchProjectByNameContext(context.Background(), namespace, projectName)
+}
 
-	req := httplib.Get(project_url)
+//SearchProjectByNameContext 同SearchProjectByName，请求受ctx控制
+func (c *Client) SearchProjectByNameContext(ctx context.Context, namespace, projectName string, opts ...RequestOption) (projectInfo ProjectInfo, err error) {
+	project_url := projectPath(namespace + "/" + projectName)
 
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+	req := c.newRequest(ctx, "GET", project_url, opts)
+	defer req.Close()
 
 	resp, err := req.Response()
 
@@ -155,20 +181,24 @@ func SearchProjectByName(namespace, projectName string) (projectInfo ProjectInfo
 
 	if resp.StatusCode == 200 {
 		err = req.ToJSON(&projectInfo)
+		projectInfo.normalize()
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 	return
 }
 
 //通过项目ID查询项目信息
-func SearchProjectById(projectId string) (projectInfo ProjectInfo, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + projectId
+func (c *Client) SearchProjectById(projectId string) (projectInfo ProjectInfo, err error) {
+	return c.SearchProjectByIdContext(context.Background(), projectId)
+}
 
-	req := httplib.Get(project_url)
+//SearchProjectByIdContext 同SearchProjectById，请求受ctx控制
+func (c *Client) SearchProjectByIdContext(ctx context.Context, projectId string, opts ...RequestOption) (projectInfo ProjectInfo, err error) {
+	project_url := "/projects/" + projectId
 
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+	req := c.newRequest(ctx, "GET", project_url, opts)
+	defer req.Close()
 
 	resp, err := req.Response()
 
@@ -178,20 +208,24 @@ func SearchProjectById(projectId string) (projectInfo ProjectInfo, err error) {
 
 	if resp.StatusCode == 200 {
 		err = req.ToJSON(&projectInfo)
+		projectInfo.normalize()
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 	return
 }
 
 //通过项目名称获取branch的信息
-func ListProjectBranchInfoByName(namespace, projectName, branchName string) (projectBranchInfo *ProjectBranchInfo, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + namespace + "%2F" + projectName + "/repository/branches/" + branchName
+func (c *Client) ListProjectBranchInfoByName(namespace, projectName, branchName string) (projectBranchInfo *ProjectBranchInfo, err error) {
+	return c.ListProjectBranchInfoByNameContext(context.Background(), namespace, projectName, branchName)
+}
 
-	req := httplib.Get(project_url)
+//ListProjectBranchInfoByNameContext 同ListProjectBranchInfoByName，请求受ctx控制
+func (c *Client) ListProjectBranchInfoByNameContext(ctx context.Context, namespace, projectName, branchName string, opts ...RequestOption) (projectBranchInfo *ProjectBranchInfo, err error) {
+	project_url := branchPath(namespace+"/"+projectName, branchName)
 
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+	req := c.newRequest(ctx, "GET", project_url, opts)
+	defer req.Close()
 
 	resp, err := req.Response()
 
@@ -219,19 +253,22 @@ func ListProjectBranchInfoByName(namespace, projectName, branchName string) (pro
 		projectBranchInfo.CommitMsg = js.Get("commit").Get("message").MustString()
 		projectBranchInfo.ParentCommitIds = js.Get("commit").Get("parent_ids").MustStringArray()
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 	return
 }
 
 //通过项目ID获取branch的信息
-func ListProjectBranchInfoById(projectId, branchName string) (projectBranchInfo *ProjectBranchInfo, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + projectId + "/repository/branches/" + branchName
+func (c *Client) ListProjectBranchInfoById(projectId, branchName string) (projectBranchInfo *ProjectBranchInfo, err error) {
+	return c.ListProjectBranchInfoByIdContext(context.Background(), projectId, branchName)
+}
 
-	req := httplib.Get(project_url)
+//ListProjectBranchInfoByIdContext 同ListProjectBranchInfoById，请求受ctx控制
+func (c *Client) ListProjectBranchInfoByIdContext(ctx context.Context, projectId, branchName string, opts ...RequestOption) (projectBranchInfo *ProjectBranchInfo, err error) {
+	project_url := "/projects/" + projectId + "/repository/branches/" + branchName
 
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+	req := c.newRequest(ctx, "GET", project_url, opts)
+	defer req.Close()
 
 	resp, err := req.Response()
 
@@ -260,21 +297,27 @@ func ListProjectBranchInfoById(projectId, branchName string) (projectBranchInfo
 		projectBranchInfo.ParentCommitIds = js.Get("commit").Get("parent_ids").MustStringArray()
 
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 	return
 }
 
 //获取文件的最新内容,包括content和commit_id等
-func GetFileContentRepo(projectId, branchName, filepath string) (repoFile RepoFile, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + projectId + "/repository/files"
+func (c *Client) GetFileContentRepo(projectId, branchName, filepath string) (repoFile RepoFile, err error) {
+	return c.GetFileContentRepoContext(context.Background(), projectId, branchName, filepath)
+}
 
-	req := httplib.Get(project_url)
+//GetFileContentRepoContext 同GetFileContentRepo，请求受ctx控制
+func (c *Client) GetFileContentRepoContext(ctx context.Context, projectId, branchName, filepath string, opts ...RequestOption) (repoFile RepoFile, err error) {
+	v4 := c.isV4(ctx)
+	project_url := "/projects/" + projectId + filesPath(filepath, v4)
 
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+	req := c.newRequest(ctx, "GET", project_url, opts)
+	defer req.Close()
 
-	req.Param("file_path", filepath)
+	if !v4 {
+		req.Param("file_path", filepath)
+	}
 	req.Param("ref", branchName)
 
 	resp, err := req.Response()
@@ -286,22 +329,28 @@ func GetFileContentRepo(projectId, branchName, filepath string) (repoFile RepoFi
 	if resp.StatusCode == 200 {
 		err = req.ToJSON(&repoFile)
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 	return
 }
 
 //在项目中创建新的文件
-func CreateNewFileRepo(projectId, branchName, filepath, content, commitMsg string) (repoUpdateFile RepoUpdateFile, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + projectId + "/repository/files"
+func (c *Client) CreateNewFileRepo(projectId, branchName, filepath, content, commitMsg string) (repoUpdateFile RepoUpdateFile, err error) {
+	return c.CreateNewFileRepoContext(context.Background(), projectId, branchName, filepath, content, commitMsg)
+}
 
-	req := httplib.Post(project_url)
+//CreateNewFileRepoContext 同CreateNewFileRepo，请求受ctx控制
+func (c *Client) CreateNewFileRepoContext(ctx context.Context, projectId, branchName, filepath, content, commitMsg string, opts ...RequestOption) (repoUpdateFile RepoUpdateFile, err error) {
+	v4 := c.isV4(ctx)
+	project_url := "/projects/" + projectId + filesPath(filepath, v4)
 
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+	req := c.newRequest(ctx, "POST", project_url, opts)
+	defer req.Close()
 
-	req.Param("file_path", filepath)
-	req.Param("branch_name", branchName)
+	if !v4 {
+		req.Param("file_path", filepath)
+	}
+	req.Param(branchParam(v4), branchName)
 	req.Param("content", content)
 	req.Param("encoding", "text")
 	req.Param("commit_message", commitMsg)
@@ -314,24 +363,31 @@ func CreateNewFileRepo(projectId, branchName, filepath, content, commitMsg strin
 
 	if resp.StatusCode == 201 || resp.StatusCode == 200 {
 		err = req.ToJSON(&repoUpdateFile)
+		repoUpdateFile.fill(filepath, branchName)
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 
 	return
 }
 
 //更新项目中文件的内容
-func UpdateExistFileRepo(projectId, branchName, filepath, content, commitMsg string) (repoUpdateFile RepoUpdateFile, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + projectId + "/repository/files"
+func (c *Client) UpdateExistFileRepo(projectId, branchName, filepath, content, commitMsg string) (repoUpdateFile RepoUpdateFile, err error) {
+	return c.UpdateExistFileRepoContext(context.Background(), projectId, branchName, filepath, content, commitMsg)
+}
 
-	req := httplib.Put(project_url)
+//UpdateExistFileRepoContext 同UpdateExistFileRepo，请求受ctx控制
+func (c *Client) UpdateExistFileRepoContext(ctx context.Context, projectId, branchName, filepath, content, commitMsg string, opts ...RequestOption) (repoUpdateFile RepoUpdateFile, err error) {
+	v4 := c.isV4(ctx)
+	project_url := "/projects/" + projectId + filesPath(filepath, v4)
 
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+	req := c.newRequest(ctx, "PUT", project_url, opts)
+	defer req.Close()
 
-	req.Param("file_path", filepath)
-	req.Param("branch_name", branchName)
+	if !v4 {
+		req.Param("file_path", filepath)
+	}
+	req.Param(branchParam(v4), branchName)
 	req.Param("content", content)
 	req.Param("encoding", "text")
 	req.Param("commit_message", commitMsg)
@@ -344,24 +400,31 @@ func UpdateExistFileRepo(projectId, branchName, filepath, content, commitMsg str
 
 	if resp.StatusCode == 200 {
 		err = req.ToJSON(&repoUpdateFile)
+		repoUpdateFile.fill(filepath, branchName)
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 
 	return
 }
 
-//删除项目中已存在的文件，该功能暂时不能用，会返回400，是权限的问题
-func DeleteExistFileRepo(projectId, branchName, filepath, commitMsg string) (repoUpdateFile RepoUpdateFile, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + projectId + "/repository/files"
+//删除项目中已存在的文件
+func (c *Client) DeleteExistFileRepo(projectId, branchName, filepath, commitMsg string) (repoUpdateFile RepoUpdateFile, err error) {
+	return c.DeleteExistFileRepoContext(context.Background(), projectId, branchName, filepath, commitMsg)
+}
 
-	req := httplib.Delete(project_url)
+//DeleteExistFileRepoContext 同DeleteExistFileRepo，请求受ctx控制
+func (c *Client) DeleteExistFileRepoContext(ctx context.Context, projectId, branchName, filepath, commitMsg string, opts ...RequestOption) (repoUpdateFile RepoUpdateFile, err error) {
+	v4 := c.isV4(ctx)
+	project_url := "/projects/" + projectId + filesPath(filepath, v4)
 
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+	req := c.newRequest(ctx, "DELETE", project_url, opts)
+	defer req.Close()
 
-	req.Param("file_path", filepath)
-	req.Param("branch_name", branchName)
+	if !v4 {
+		req.Param("file_path", filepath)
+	}
+	req.Param(branchParam(v4), branchName)
 	req.Param("commit_message", commitMsg)
 
 	resp, err := req.Response()
@@ -370,85 +433,69 @@ func DeleteExistFileRepo(projectId, branchName, filepath, commitMsg string) (rep
 		return
 	}
 
-	if resp.StatusCode == 200 {
+	//v4删除成功时返回204，没有返回内容
+	if resp.StatusCode == 204 {
+		repoUpdateFile = RepoUpdateFile{FilePath: filepath, BranchName: branchName}
+	} else if resp.StatusCode == 200 {
 		err = req.ToJSON(&repoUpdateFile)
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 
 	return
 }
 
-//func DeleteExistFileRepo2(projectId, branchName, filepath, commitMsg string) (repoUpdateFile RepoUpdateFile, err error) {
-//	project_url := config.GitUrl + config.APIVersion + "/projects/" + projectId + "/repository/files"
-
-//	q := struct {
-//		Filepath   string `json:"file_path"`
-//		BranchName string `json:"branch_name"`
-//		CommitMsg  string `json:"commit_message"`
-//	}{
-//		Filepath:   filepath,
-//		BranchName: branchName,
-//		CommitMsg:  commitMsg,
-//	}
-
-//	resp, body, errs := goreq.New().Timeout(6000*time.Millisecond).
-//		Delete(project_url).
-//		ContentType("application/json").
-//		SetHeader("PRIVATE-TOKEN", config.AdminToken).SendStruct(q).End()
-
-//	if len(errs) > 0 {
-//		err = errs[0]
-//		return
-//	}
+//根据子目录获取该目录下的文件或子目录信息，不会自动递归子目录查询，自动获取所有分页
+func (c *Client) ListRepoTreeByDirectory(projectId, branchName, filepath string) (repoTrees []RepoTree, err error) {
+	return c.ListRepoTreeByDirectoryContext(context.Background(), projectId, branchName, filepath)
+}
 
-//	if resp.StatusCode == 200 {
-//		err = json.Unmarshal(body, &repoUpdateFile)
-//		//err = req.ToJSON(&repoUpdateFile)
-//	} else {
-//		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
-//	}
+//ListRepoTreeByDirectoryContext 同ListRepoTreeByDirectory，请求受ctx控制
+func (c *Client) ListRepoTreeByDirectoryContext(ctx context.Context, projectId, branchName, filepath string, opts ...RequestOption) (repoTrees []RepoTree, err error) {
+	return CollectAll(c.RepoTreeIterator(ctx, projectId, branchName, filepath, ListOptions{PerPage: c.perPage()}, opts...))
+}
 
-//	return
-//}
+//获取项目根目录下的所有子目录和文件信息，不会递归查询，自动获取所有分页
+func (c *Client) ListRepoTree(projectId, branchName string) (repoTrees []RepoTree, err error) {
+	return c.ListRepoTreeContext(context.Background(), projectId, branchName)
+}
 
-//根据子目录获取该目录下的文件或子目录信息，不会自动递归子目录查询
-func ListRepoTreeByDirectory(projectId, branchName, filepath string) (repoTrees []RepoTree, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + projectId + "/repository/tree"
+//ListRepoTreeContext 同ListRepoTree，请求受ctx控制
+func (c *Client) ListRepoTreeContext(ctx context.Context, projectId, branchName string, opts ...RequestOption) (repoTrees []RepoTree, err error) {
+	return c.ListRepoTreeByDirectoryContext(ctx, projectId, branchName, "", opts...)
+}
 
-	req := httplib.Get(project_url)
+//RepoTreeIterator 逐页遍历目录下的文件和子目录，filepath为空时遍历根目录
+func (c *Client) RepoTreeIterator(ctx context.Context, projectId, branchName, filepath string, listOpt ListOptions, opts ...RequestOption) *Iterator[RepoTree] {
+	return NewIterator(ctx, listOpt, func(ctx context.Context, listOpt ListOptions) ([]RepoTree, *PageInfo, error) {
+		return c.ListRepoTreePage(ctx, projectId, branchName, filepath, listOpt, opts...)
+	})
+}
 
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+//ListRepoTreePage 获取目录下文件和子目录的一页数据，filepath为空时获取根目录
+func (c *Client) ListRepoTreePage(ctx context.Context, projectId, branchName, filepath string, listOpt ListOptions, opts ...RequestOption) (repoTrees []RepoTree, pageInfo *PageInfo, err error) {
+	return c.listRepoTreePage(ctx, projectId, branchName, filepath, false, listOpt, opts)
+}
 
-	req.Param("path", filepath)
-	req.Param("ref_name", branchName)
+//recursive只在v4中有效，v3不返回path时根据filepath补全
+func (c *Client) listRepoTreePage(ctx context.Context, projectId, branchName, filepath string, recursive bool, listOpt ListOptions, opts []RequestOption) (repoTrees []RepoTree, pageInfo *PageInfo, err error) {
+	project_url := "/projects/" + projectId + "/repository/tree"
 
-	resp, err := req.Response()
+	req := c.newRequest(ctx, "GET", project_url, opts)
+	defer req.Close()
 
-	if err != nil {
-		return
+	if filepath != "" {
+		req.Param("path", filepath)
 	}
-
-	if resp.StatusCode == 200 {
-		err = req.ToJSON(&repoTrees)
+	if c.isV4(ctx) {
+		req.Param("ref", branchName)
+		if recursive {
+			req.Param("recursive", "true")
+		}
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		req.Param("ref_name", branchName)
 	}
-
-	return
-}
-
-//获取项目根目录下的所有子目录和文件信息，不会递归查询
-func ListRepoTree(projectId, branchName string) (repoTrees []RepoTree, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + projectId + "/repository/tree"
-
-	req := httplib.Get(project_url)
-
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
-
-	req.Param("ref_name", branchName)
+	listOpt.apply(req)
 
 	resp, err := req.Response()
 
@@ -457,24 +504,41 @@ func ListRepoTree(projectId, branchName string) (repoTrees []RepoTree, err error
 	}
 
 	if resp.StatusCode == 200 {
+		pageInfo = parsePageInfo(resp.Header)
 		err = req.ToJSON(&repoTrees)
+		for i := range repoTrees {
+			if repoTrees[i].Path == "" {
+				repoTrees[i].Path = path.Join(filepath, repoTrees[i].Name)
+			}
+		}
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 
 	return
 }
 
 //根据commitid获取文件的内容
-func GetFileContentByCommitid(projectId, sha, filepath string) (content string, err error) {
-	project_url := config.GitUrl + config.APIVersion + "/projects/" + projectId + "/repository/blobs/" + sha
+func (c *Client) GetFileContentByCommitid(projectId, sha, filepath string) (content string, err error) {
+	return c.GetFileContentByCommitidContext(context.Background(), projectId, sha, filepath)
+}
 
-	req := httplib.Get(project_url)
+//GetFileContentByCommitidContext 同GetFileContentByCommitid，请求受ctx控制
+func (c *Client) GetFileContentByCommitidContext(ctx context.Context, projectId, sha, filepath string, opts ...RequestOption) (content string, err error) {
+	v4 := c.isV4(ctx)
+	project_url := "/projects/" + projectId + "/repository/blobs/" + sha
+	if v4 {
+		project_url = "/projects/" + projectId + filesPath(filepath, v4) + "/raw"
+	}
 
-	req.Header("Content-Type", "application/json")
-	req.Header("PRIVATE-TOKEN", config.AdminToken)
+	req := c.newRequest(ctx, "GET", project_url, opts)
+	defer req.Close()
 
-	req.Param("filepath", filepath)
+	if v4 {
+		req.Param("ref", sha)
+	} else {
+		req.Param("filepath", filepath)
+	}
 
 	resp, err := req.Response()
 
@@ -485,7 +549,7 @@ func GetFileContentByCommitid(projectId, sha, filepath string) (content string,
 	if resp.StatusCode == 200 {
 		content, err = req.String()
 	} else {
-		err = util.NewError("Http Connect Error, Status:%s", resp.Status)
+		err = req.apiError(resp)
 	}
 
 	return
@@ -0,0 +1,231 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+//Hook 请求和返回的钩子，拿到的Header、URL、请求体和返回内容中的Token等敏感信息都已脱敏
+type Hook interface {
+	//BeforeRequest 请求发送前调用
+	BeforeRequest(ctx context.Context, req *HookRequest)
+	//AfterResponse 返回内容读取完毕、请求出错或请求关闭时调用，每个请求只调用一次
+	AfterResponse(ctx context.Context, resp *HookResponse)
+}
+
+//HookRequest 请求信息
+type HookRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+//HookResponse 返回信息，Body只有在返回内容被完整读取时才有值，流式下载的内容不会保存
+type HookResponse struct {
+	Request    *HookRequest
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+	Latency    time.Duration //从发送请求到收到返回Header的时间
+	Err        error
+}
+
+//需要脱敏的Header、URL参数和JSON字段，另外以_token结尾的JSON字段（例如runners_token）也会脱敏
+var (
+	secretHeaders = []string{"Private-Token", "Job-Token", "Authorization", "Cookie", "Set-Cookie"}
+	secretParams  = []string{"private_token", "access_token", "job_token", "token", "password", "refresh_token", "secret"}
+)
+
+const redacted = "[REDACTED]"
+
+func newHookRequest(req *http.Request, body []byte) *HookRequest {
+	return &HookRequest{
+		Method: req.Method,
+		URL:    redactURL(req.URL),
+		Header: redactHeader(req.Header),
+		Body:   redactBody(body),
+	}
+}
+
+//CurlCommand 生成可以复现该请求的curl命令
+func (r *HookRequest) CurlCommand() string {
+	parts := []string{"curl", "-X", r.Method, shellQuote(r.URL)}
+
+	keys := make([]string, 0, len(r.Header))
+	for key := range r.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range r.Header[key] {
+			parts = append(parts, "-H", shellQuote(key+": "+value))
+		}
+	}
+
+	if len(r.Body) > 0 {
+		parts = append(parts, "--data-raw", shellQuote(string(r.Body)))
+	}
+	return strings.Join(parts, " ")
+}
+
+//单引号包裹，内部的单引号转义为 '\”
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func redactHeader(header http.Header) http.Header {
+	h := header.Clone()
+	for _, key := range secretHeaders {
+		if h.Get(key) != "" {
+			h.Set(key, redacted)
+		}
+	}
+	return h
+}
+
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	changed := false
+	for _, key := range secretParams {
+		if query.Get(key) != "" {
+			query.Set(key, redacted)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return u.String()
+	}
+
+	copied := *u
+	copied.RawQuery = query.Encode()
+	return copied.String()
+}
+
+//请求体或返回内容为JSON或表单时对敏感字段脱敏，JSON中嵌套的对象和数组也会处理
+func redactBody(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if decoder.Decode(&value) == nil && !decoder.More() {
+		if !redactJSON(value) {
+			return body
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return body
+		}
+		return data
+	}
+
+	if form, err := url.ParseQuery(string(body)); err == nil {
+		changed := false
+		for _, key := range secretParams {
+			if form.Get(key) != "" {
+				form.Set(key, redacted)
+				changed = true
+			}
+		}
+		if changed {
+			return []byte(form.Encode())
+		}
+	}
+	return body
+}
+
+//将JSON中的敏感字段替换为[REDACTED]，返回是否有修改
+func redactJSON(value interface{}) (changed bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSecretField(key) {
+				v[key] = redacted
+				changed = true
+			} else if redactJSON(field) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactJSON(item) {
+				changed = true
+			}
+		}
+	}
+	return
+}
+
+func isSecretField(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretParams {
+		if key == secret {
+			return true
+		}
+	}
+	return strings.HasSuffix(key, "_token")
+}
+
+/*
+LoggingHook 打印请求的方法、地址、状态码和耗时，可以选择打印请求和返回内容以及等价的curl命令
+用法：client.Hooks = append(client.Hooks, &gitlab.LoggingHook{Curl: true})
+*/
+type LoggingHook struct {
+	Logger      *log.Logger //为nil时使用log包的默认Logger
+	LogBodies   bool        //是否打印请求和返回内容
+	Curl        bool        //是否打印curl命令
+	MaxBodySize int         //打印内容的最大长度，为0时不限制
+}
+
+func (h *LoggingHook) BeforeRequest(ctx context.Context, req *HookRequest) {
+	if h.Curl {
+		h.printf("gitlab: %s", req.CurlCommand())
+	}
+	if h.LogBodies && len(req.Body) > 0 {
+		h.printf("gitlab: %s %s request body: %s", req.Method, req.URL, h.truncate(req.Body))
+	}
+}
+
+func (h *LoggingHook) AfterResponse(ctx context.Context, resp *HookResponse) {
+	req := resp.Request
+
+	if resp.Err != nil {
+		h.printf("gitlab: %s %s error: %s (%s)", req.Method, req.URL, resp.Err, resp.Latency)
+		return
+	}
+
+	h.printf("gitlab: %s %s %s (%s)", req.Method, req.URL, resp.Status, resp.Latency)
+	if h.LogBodies && len(resp.Body) > 0 {
+		h.printf("gitlab: %s %s response body: %s", req.Method, req.URL, h.truncate(resp.Body))
+	}
+}
+
+func (h *LoggingHook) truncate(body []byte) string {
+	if h.MaxBodySize > 0 && len(body) > h.MaxBodySize {
+		return string(body[:h.MaxBodySize]) + fmt.Sprintf("...(%d bytes)", len(body))
+	}
+	return string(body)
+}
+
+func (h *LoggingHook) printf(format string, v ...interface{}) {
+	if h.Logger != nil {
+		h.Logger.Printf(format, v...)
+	} else {
+		log.Printf(format, v...)
+	}
+}
@@ -0,0 +1,264 @@
+package gitlab
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Issue 项目的issue，issue相关的接口只支持v4，项目内使用Iid定位issue
+type Issue struct {
+	Id             int        `json:"id"`
+	Iid            int        `json:"iid"`
+	ProjectId      int        `json:"project_id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	State          string     `json:"state"` //opened或closed
+	Author         *User      `json:"author"`
+	Assignees      []User     `json:"assignees"`
+	Labels         []string   `json:"labels"`
+	Milestone      *Milestone `json:"milestone"`
+	Confidential   bool       `json:"confidential"`
+	DueDate        string     `json:"due_date"` //格式为2006-01-02
+	UserNotesCount int        `json:"user_notes_count"`
+	MovedToId      int        `json:"moved_to_id"` //移动到其它项目后新issue的Id
+	ClosedBy       *User      `json:"closed_by"`
+	ClosedAt       *time.Time `json:"closed_at"`
+	CreatedAt      *time.Time `json:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at"`
+	WebUrl         string     `json:"web_url"`
+}
+
+//issue接口的路径
+func issuePath(projectId string, issueIid int) string {
+	return projectPath(projectId) + "/issues/" + strconv.Itoa(issueIid)
+}
+
+//projectId为空时列出当前用户可以访问的所有issue
+func issuesPath(projectId string) string {
+	if projectId == "" {
+		return "/issues"
+	}
+	return projectPath(projectId) + "/issues"
+}
+
+//ListIssuesOptions issue列表的过滤条件
+type ListIssuesOptions struct {
+	ListOptions
+	State         string   //opened或closed，为空时返回全部
+	Scope         string   //created_by_me、assigned_to_me、all，不指定项目时默认为created_by_me
+	Labels        []string //同时包含所有标签
+	NotLabels     []string //不包含这些标签
+	Milestone     string   //里程碑标题
+	Search        string   //按标题和描述搜索
+	AuthorId      int
+	AssigneeId    int
+	Iids          []int
+	Confidential  *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+}
+
+func (o ListIssuesOptions) setParams(req *request) {
+	for key, value := range map[string]string{
+		"state":       o.State,
+		"scope":       o.Scope,
+		"labels":      strings.Join(o.Labels, ","),
+		"not[labels]": strings.Join(o.NotLabels, ","),
+		"milestone":   o.Milestone,
+		"search":      o.Search,
+	} {
+		if value != "" {
+			req.Param(key, value)
+		}
+	}
+
+	for key, value := range map[string]int{
+		"author_id":   o.AuthorId,
+		"assignee_id": o.AssigneeId,
+	} {
+		if value > 0 {
+			req.Param(key, strconv.Itoa(value))
+		}
+	}
+
+	for _, iid := range o.Iids {
+		req.Param("iids[]", strconv.Itoa(iid))
+	}
+	if o.Confidential != nil {
+		req.Param("confidential", strconv.FormatBool(*o.Confidential))
+	}
+
+	for key, value := range map[string]*time.Time{
+		"created_after":  o.CreatedAfter,
+		"created_before": o.CreatedBefore,
+		"updated_after":  o.UpdatedAfter,
+	} {
+		if value != nil {
+			req.Param(key, value.Format(time.RFC3339))
+		}
+	}
+}
+
+//ListIssues 列出项目的issue，projectId为空时列出所有项目中符合条件的issue，自动获取所有分页
+func (c *Client) ListIssues(ctx context.Context, projectId string, opt ListIssuesOptions, opts ...RequestOption) (issues []Issue, err error) {
+	return listAll[Issue](ctx, c, issuesPath(projectId), opt.ListOptions, opt.setParams, opts)
+}
+
+//ListIssuesPage 获取issue列表的一页数据
+func (c *Client) ListIssuesPage(ctx context.Context, projectId string, opt ListIssuesOptions, opts ...RequestOption) (issues []Issue, pageInfo *PageInfo, err error) {
+	return listPage[Issue](ctx, c, issuesPath(projectId), opt.ListOptions, opt.setParams, opts)
+}
+
+//IssueIterator 逐页遍历issue
+func (c *Client) IssueIterator(ctx context.Context, projectId string, opt ListIssuesOptions, opts ...RequestOption) *Iterator[Issue] {
+	return listIterator[Issue](ctx, c, issuesPath(projectId), opt.ListOptions, opt.setParams, opts)
+}
+
+//GetIssue 获取单个issue
+func (c *Client) GetIssue(ctx context.Context, projectId string, issueIid int, opts ...RequestOption) (issue *Issue, err error) {
+	return doJSON[Issue](ctx, c, "GET", issuePath(projectId, issueIid), nil, opts)
+}
+
+//CreateIssueOptions 创建issue的参数
+type CreateIssueOptions struct {
+	Title        string
+	Description  string
+	AssigneeIds  []int
+	Labels       []string //不存在的标签会自动创建
+	MilestoneId  int
+	DueDate      string //格式为2006-01-02
+	Confidential bool
+}
+
+func (o CreateIssueOptions) body() map[string]interface{} {
+	body := map[string]interface{}{
+		"title": o.Title,
+	}
+	if o.Description != "" {
+		body["description"] = o.Description
+	}
+	if len(o.AssigneeIds) > 0 {
+		body["assignee_ids"] = o.AssigneeIds
+	}
+	if len(o.Labels) > 0 {
+		body["labels"] = strings.Join(o.Labels, ",")
+	}
+	if o.MilestoneId > 0 {
+		body["milestone_id"] = o.MilestoneId
+	}
+	if o.DueDate != "" {
+		body["due_date"] = o.DueDate
+	}
+	if o.Confidential {
+		body["confidential"] = true
+	}
+	return body
+}
+
+//CreateIssue 创建issue
+func (c *Client) CreateIssue(ctx context.Context, projectId string, opt CreateIssueOptions, opts ...RequestOption) (issue *Issue, err error) {
+	return doJSON[Issue](ctx, c, "POST", projectPath(projectId)+"/issues", opt.body(), opts)
+}
+
+//UpdateIssueOptions 更新issue的参数，字符串为空、切片为nil、指针为nil时不修改
+type UpdateIssueOptions struct {
+	Title        string
+	Description  string
+	AssigneeIds  []int    //为空切片时取消所有指派
+	Labels       []string //替换所有标签，为空切片时清空标签
+	AddLabels    []string
+	RemoveLabels []string
+	MilestoneId  *int    //为0时取消里程碑
+	DueDate      *string //为空字符串时取消截止日期
+	Confidential *bool
+	StateEvent   string //close或reopen
+}
+
+func (o UpdateIssueOptions) body() map[string]interface{} {
+	body := map[string]interface{}{}
+
+	for key, value := range map[string]string{
+		"title":         o.Title,
+		"description":   o.Description,
+		"add_labels":    strings.Join(o.AddLabels, ","),
+		"remove_labels": strings.Join(o.RemoveLabels, ","),
+		"state_event":   o.StateEvent,
+	} {
+		if value != "" {
+			body[key] = value
+		}
+	}
+
+	if o.AssigneeIds != nil {
+		body["assignee_ids"] = o.AssigneeIds
+	}
+	if o.Labels != nil {
+		body["labels"] = strings.Join(o.Labels, ",")
+	}
+	if o.MilestoneId != nil {
+		body["milestone_id"] = *o.MilestoneId
+	}
+	if o.DueDate != nil {
+		body["due_date"] = *o.DueDate
+	}
+	if o.Confidential != nil {
+		body["confidential"] = *o.Confidential
+	}
+	return body
+}
+
+//UpdateIssue 更新issue的标题、描述、指派、标签等
+func (c *Client) UpdateIssue(ctx context.Context, projectId string, issueIid int, opt UpdateIssueOptions, opts ...RequestOption) (issue *Issue, err error) {
+	return doJSON[Issue](ctx, c, "PUT", issuePath(projectId, issueIid), opt.body(), opts)
+}
+
+//CloseIssue 关闭issue
+func (c *Client) CloseIssue(ctx context.Context, projectId string, issueIid int, opts ...RequestOption) (issue *Issue, err error) {
+	return c.UpdateIssue(ctx, projectId, issueIid, UpdateIssueOptions{StateEvent: "close"}, opts...)
+}
+
+//ReopenIssue 重新打开已关闭的issue
+func (c *Client) ReopenIssue(ctx context.Context, projectId string, issueIid int, opts ...RequestOption) (issue *Issue, err error) {
+	return c.UpdateIssue(ctx, projectId, issueIid, UpdateIssueOptions{StateEvent: "reopen"}, opts...)
+}
+
+//MoveIssue 将issue移动到其它项目，原issue被关闭，返回新项目中的issue
+func (c *Client) MoveIssue(ctx context.Context, projectId string, issueIid int, toProjectId string, opts ...RequestOption) (issue *Issue, err error) {
+	return doJSON[Issue](ctx, c, "POST", issuePath(projectId, issueIid)+"/move", map[string]string{"to_project_id": toProjectId}, opts)
+}
+
+//DeleteIssue 删除issue，需要管理员或项目Owner权限
+func (c *Client) DeleteIssue(ctx context.Context, projectId string, issueIid int, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", issuePath(projectId, issueIid), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//ListIssueNotes 列出issue的评论，包括系统记录，自动获取所有分页
+func (c *Client) ListIssueNotes(ctx context.Context, projectId string, issueIid int, opt ListOptions, opts ...RequestOption) (notes []Note, err error) {
+	return listAll[Note](ctx, c, issuePath(projectId, issueIid)+"/notes", opt, nil, opts)
+}
+
+//CreateIssueNote 在issue上添加评论
+func (c *Client) CreateIssueNote(ctx context.Context, projectId string, issueIid int, body string, opts ...RequestOption) (note *Note, err error) {
+	return doJSON[Note](ctx, c, "POST", issuePath(projectId, issueIid)+"/notes", map[string]string{"body": body}, opts)
+}
+
+//UpdateIssueNote 修改issue上的评论
+func (c *Client) UpdateIssueNote(ctx context.Context, projectId string, issueIid, noteId int, body string, opts ...RequestOption) (note *Note, err error) {
+	return doJSON[Note](ctx, c, "PUT", issuePath(projectId, issueIid)+"/notes/"+strconv.Itoa(noteId), map[string]string{"body": body}, opts)
+}
+
+//DeleteIssueNote 删除issue上的评论
+func (c *Client) DeleteIssueNote(ctx context.Context, projectId string, issueIid, noteId int, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", issuePath(projectId, issueIid)+"/notes/"+strconv.Itoa(noteId), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
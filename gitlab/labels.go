@@ -0,0 +1,102 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+//Label 项目的标签
+type Label struct {
+	Id                     int    `json:"id"`
+	Name                   string `json:"name"`
+	Color                  string `json:"color"` //例如 #FF0000
+	TextColor              string `json:"text_color"`
+	Description            string `json:"description"`
+	Priority               *int   `json:"priority"`
+	Subscribed             bool   `json:"subscribed"` //当前用户是否订阅
+	IsProjectLabel         bool   `json:"is_project_label"`
+	OpenIssuesCount        int    `json:"open_issues_count"`
+	ClosedIssuesCount      int    `json:"closed_issues_count"`
+	OpenMergeRequestsCount int    `json:"open_merge_requests_count"`
+}
+
+//LabelOptions 创建或更新标签的参数，更新时为空的字段不修改
+type LabelOptions struct {
+	Name        string //更新时为新的名称
+	Color       string //#开头的十六进制颜色或CSS颜色名
+	Description string
+	Priority    *int
+}
+
+func (o LabelOptions) body(nameKey string) map[string]interface{} {
+	body := map[string]interface{}{}
+	if o.Name != "" {
+		body[nameKey] = o.Name
+	}
+	if o.Color != "" {
+		body["color"] = o.Color
+	}
+	if o.Description != "" {
+		body["description"] = o.Description
+	}
+	if o.Priority != nil {
+		body["priority"] = *o.Priority
+	}
+	return body
+}
+
+//标签接口的路径，name可以是标签名或Id
+func labelPath(projectId, name string) string {
+	return projectPath(projectId) + "/labels/" + url.PathEscape(name)
+}
+
+//ListLabels 列出项目的标签，包含issue和合并请求的数量，自动获取所有分页
+func (c *Client) ListLabels(ctx context.Context, projectId string, opt ListOptions, opts ...RequestOption) (labels []Label, err error) {
+	return listAll[Label](ctx, c, projectPath(projectId)+"/labels", opt, func(req *request) {
+		req.Param("with_counts", "true")
+	}, opts)
+}
+
+//GetLabel 获取单个标签
+func (c *Client) GetLabel(ctx context.Context, projectId, name string, opts ...RequestOption) (label *Label, err error) {
+	return doJSON[Label](ctx, c, "GET", labelPath(projectId, name), nil, opts)
+}
+
+//CreateLabel 创建标签，Name和Color必须设置
+func (c *Client) CreateLabel(ctx context.Context, projectId string, opt LabelOptions, opts ...RequestOption) (label *Label, err error) {
+	return doJSON[Label](ctx, c, "POST", projectPath(projectId)+"/labels", opt.body("name"), opts)
+}
+
+//UpdateLabel 修改标签，opt.Name不为空时重命名
+func (c *Client) UpdateLabel(ctx context.Context, projectId, name string, opt LabelOptions, opts ...RequestOption) (label *Label, err error) {
+	return doJSON[Label](ctx, c, "PUT", labelPath(projectId, name), opt.body("new_name"), opts)
+}
+
+//DeleteLabel 删除标签
+func (c *Client) DeleteLabel(ctx context.Context, projectId, name string, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", labelPath(projectId, name), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//SubscribeLabel 订阅标签，使用该标签的issue和合并请求有变化时收到通知，已订阅时直接返回标签
+func (c *Client) SubscribeLabel(ctx context.Context, projectId, name string, opts ...RequestOption) (label *Label, err error) {
+	return c.subscribeLabel(ctx, projectId, name, "/subscribe", opts)
+}
+
+//UnsubscribeLabel 取消订阅标签，未订阅时直接返回标签
+func (c *Client) UnsubscribeLabel(ctx context.Context, projectId, name string, opts ...RequestOption) (label *Label, err error) {
+	return c.subscribeLabel(ctx, projectId, name, "/unsubscribe", opts)
+}
+
+//订阅状态没有变化时Gitlab返回304
+func (c *Client) subscribeLabel(ctx context.Context, projectId, name, action string, opts []RequestOption) (label *Label, err error) {
+	label, err = doJSON[Label](ctx, c, "POST", labelPath(projectId, name)+action, nil, opts)
+	if hasStatus(err, http.StatusNotModified) {
+		return c.GetLabel(ctx, projectId, name, opts...)
+	}
+	return
+}
@@ -0,0 +1,329 @@
+package gitlab
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+MergeRequest 合并请求，合并请求相关的接口只支持v4
+项目内使用Iid（页面上显示的!编号）定位合并请求，Id是全局编号
+*/
+type MergeRequest struct {
+	Id                        int        `json:"id"`
+	Iid                       int        `json:"iid"`
+	ProjectId                 int        `json:"project_id"`
+	Title                     string     `json:"title"`
+	Description               string     `json:"description"`
+	State                     string     `json:"state"` //opened、closed、locked、merged
+	SourceBranch              string     `json:"source_branch"`
+	TargetBranch              string     `json:"target_branch"`
+	SourceProjectId           int        `json:"source_project_id"`
+	TargetProjectId           int        `json:"target_project_id"`
+	Author                    *User      `json:"author"`
+	Assignees                 []User     `json:"assignees"`
+	Reviewers                 []User     `json:"reviewers"`
+	Labels                    []string   `json:"labels"`
+	Milestone                 *Milestone `json:"milestone"`
+	Draft                     bool       `json:"draft"`
+	MergeStatus               string     `json:"merge_status"`          //can_be_merged、cannot_be_merged、checking等
+	DetailedMergeStatus       string     `json:"detailed_merge_status"` //不能合并的具体原因，例如 conflict、ci_must_pass
+	HasConflicts              bool       `json:"has_conflicts"`
+	Sha                       string     `json:"sha"` //源分支的最新提交
+	MergeCommitSha            string     `json:"merge_commit_sha"`
+	SquashCommitSha           string     `json:"squash_commit_sha"`
+	Squash                    bool       `json:"squash"`
+	ShouldRemoveSourceBranch  bool       `json:"should_remove_source_branch"`
+	ForceRemoveSourceBranch   bool       `json:"force_remove_source_branch"`
+	MergeWhenPipelineSucceeds bool       `json:"merge_when_pipeline_succeeds"`
+	RebaseInProgress          bool       `json:"rebase_in_progress"`
+	MergeError                string     `json:"merge_error"`
+	ChangesCount              string     `json:"changes_count"` //文件数过多时为 "1000+"
+	Changes                   []Diff     `json:"changes"`       //只有GetMergeRequestChanges返回
+	DiffRefs                  *DiffRefs  `json:"diff_refs"`
+	MergedBy                  *User      `json:"merged_by"`
+	MergedAt                  *time.Time `json:"merged_at"`
+	ClosedAt                  *time.Time `json:"closed_at"`
+	CreatedAt                 *time.Time `json:"created_at"`
+	UpdatedAt                 *time.Time `json:"updated_at"`
+	WebUrl                    string     `json:"web_url"`
+}
+
+//DiffRefs 合并请求当前版本的diff基准，在diff上添加评论时需要
+type DiffRefs struct {
+	BaseSha  string `json:"base_sha"`
+	HeadSha  string `json:"head_sha"`
+	StartSha string `json:"start_sha"`
+}
+
+//合并请求接口的路径
+func mergeRequestPath(projectId string, mergeRequestIid int) string {
+	return projectPath(projectId) + "/merge_requests/" + strconv.Itoa(mergeRequestIid)
+}
+
+//ListMergeRequestsOptions 合并请求列表的过滤条件
+type ListMergeRequestsOptions struct {
+	ListOptions
+	State        string   //opened、closed、locked、merged，为空时返回全部
+	Scope        string   //created_by_me、assigned_to_me、all，不指定项目时默认为created_by_me
+	Labels       []string //同时包含所有标签
+	NotLabels    []string //不包含这些标签
+	Milestone    string   //里程碑标题
+	SourceBranch string
+	TargetBranch string
+	Search       string //按标题和描述搜索
+	AuthorId     int
+	AssigneeId   int
+	ReviewerId   int
+}
+
+func (o ListMergeRequestsOptions) setParams(req *request) {
+	for key, value := range map[string]string{
+		"state":         o.State,
+		"scope":         o.Scope,
+		"labels":        strings.Join(o.Labels, ","),
+		"not[labels]":   strings.Join(o.NotLabels, ","),
+		"milestone":     o.Milestone,
+		"source_branch": o.SourceBranch,
+		"target_branch": o.TargetBranch,
+		"search":        o.Search,
+	} {
+		if value != "" {
+			req.Param(key, value)
+		}
+	}
+
+	for key, value := range map[string]int{
+		"author_id":   o.AuthorId,
+		"assignee_id": o.AssigneeId,
+		"reviewer_id": o.ReviewerId,
+	} {
+		if value > 0 {
+			req.Param(key, strconv.Itoa(value))
+		}
+	}
+}
+
+//projectId为空时列出当前用户可以访问的所有合并请求
+func mergeRequestsPath(projectId string) string {
+	if projectId == "" {
+		return "/merge_requests"
+	}
+	return projectPath(projectId) + "/merge_requests"
+}
+
+//ListMergeRequests 列出项目的合并请求，projectId为空时列出所有项目中符合条件的合并请求，自动获取所有分页
+func (c *Client) ListMergeRequests(ctx context.Context, projectId string, opt ListMergeRequestsOptions, opts ...RequestOption) (mergeRequests []MergeRequest, err error) {
+	return listAll[MergeRequest](ctx, c, mergeRequestsPath(projectId), opt.ListOptions, opt.setParams, opts)
+}
+
+//ListMergeRequestsPage 获取合并请求列表的一页数据
+func (c *Client) ListMergeRequestsPage(ctx context.Context, projectId string, opt ListMergeRequestsOptions, opts ...RequestOption) (mergeRequests []MergeRequest, pageInfo *PageInfo, err error) {
+	return listPage[MergeRequest](ctx, c, mergeRequestsPath(projectId), opt.ListOptions, opt.setParams, opts)
+}
+
+//MergeRequestIterator 逐页遍历合并请求
+func (c *Client) MergeRequestIterator(ctx context.Context, projectId string, opt ListMergeRequestsOptions, opts ...RequestOption) *Iterator[MergeRequest] {
+	return listIterator[MergeRequest](ctx, c, mergeRequestsPath(projectId), opt.ListOptions, opt.setParams, opts)
+}
+
+//CreateMergeRequestOptions 创建合并请求的参数
+type CreateMergeRequestOptions struct {
+	SourceBranch       string
+	TargetBranch       string
+	Title              string //以 Draft: 开头时创建草稿
+	Description        string
+	AssigneeIds        []int
+	ReviewerIds        []int
+	Labels             []string
+	MilestoneId        int
+	TargetProjectId    int  //合并到fork来源等其它项目，为0时合并到当前项目
+	RemoveSourceBranch bool //合并后删除源分支
+	Squash             bool //合并时压缩为一个提交
+}
+
+func (o CreateMergeRequestOptions) body() map[string]interface{} {
+	body := map[string]interface{}{
+		"source_branch": o.SourceBranch,
+		"target_branch": o.TargetBranch,
+		"title":         o.Title,
+	}
+	if o.Description != "" {
+		body["description"] = o.Description
+	}
+	if len(o.AssigneeIds) > 0 {
+		body["assignee_ids"] = o.AssigneeIds
+	}
+	if len(o.ReviewerIds) > 0 {
+		body["reviewer_ids"] = o.ReviewerIds
+	}
+	if len(o.Labels) > 0 {
+		body["labels"] = strings.Join(o.Labels, ",")
+	}
+	if o.MilestoneId > 0 {
+		body["milestone_id"] = o.MilestoneId
+	}
+	if o.TargetProjectId > 0 {
+		body["target_project_id"] = o.TargetProjectId
+	}
+	if o.RemoveSourceBranch {
+		body["remove_source_branch"] = true
+	}
+	if o.Squash {
+		body["squash"] = true
+	}
+	return body
+}
+
+/*
+CreateMergeRequest 从源分支向目标分支创建合并请求
+用法：
+	mr, err := client.CreateMergeRequest(ctx, projectId, gitlab.CreateMergeRequestOptions{
+		SourceBranch: "bot/update-config",
+		TargetBranch: "master",
+		Title:        "Update config",
+		Labels:       []string{"bot"},
+	})
+*/
+func (c *Client) CreateMergeRequest(ctx context.Context, projectId string, opt CreateMergeRequestOptions, opts ...RequestOption) (mergeRequest *MergeRequest, err error) {
+	return doJSON[MergeRequest](ctx, c, "POST", projectPath(projectId)+"/merge_requests", opt.body(), opts)
+}
+
+//GetMergeRequest 获取单个合并请求，包含rebase是否正在进行
+func (c *Client) GetMergeRequest(ctx context.Context, projectId string, mergeRequestIid int, opts ...RequestOption) (mergeRequest *MergeRequest, err error) {
+	req := c.newRequest(ctx, "GET", mergeRequestPath(projectId, mergeRequestIid), opts)
+	defer req.Close()
+
+	req.Param("include_rebase_in_progress", "true")
+
+	mergeRequest = &MergeRequest{}
+	if _, err = req.Do(mergeRequest); err != nil {
+		mergeRequest = nil
+	}
+	return
+}
+
+//GetMergeRequestChanges 获取合并请求及其中每个文件的修改内容
+func (c *Client) GetMergeRequestChanges(ctx context.Context, projectId string, mergeRequestIid int, opts ...RequestOption) (mergeRequest *MergeRequest, err error) {
+	return doJSON[MergeRequest](ctx, c, "GET", mergeRequestPath(projectId, mergeRequestIid)+"/changes", nil, opts)
+}
+
+//UpdateMergeRequestOptions 更新合并请求的参数，字符串为空、切片为nil、指针为nil时不修改
+type UpdateMergeRequestOptions struct {
+	Title              string
+	Description        string
+	TargetBranch       string
+	AssigneeIds        []int    //为空切片时取消所有指派
+	ReviewerIds        []int    //为空切片时取消所有审核人
+	Labels             []string //替换所有标签，为空切片时清空标签
+	AddLabels          []string
+	RemoveLabels       []string
+	MilestoneId        *int //为0时取消里程碑
+	RemoveSourceBranch *bool
+	Squash             *bool
+	StateEvent         string //close或reopen
+}
+
+func (o UpdateMergeRequestOptions) body() map[string]interface{} {
+	body := map[string]interface{}{}
+
+	for key, value := range map[string]string{
+		"title":         o.Title,
+		"description":   o.Description,
+		"target_branch": o.TargetBranch,
+		"add_labels":    strings.Join(o.AddLabels, ","),
+		"remove_labels": strings.Join(o.RemoveLabels, ","),
+		"state_event":   o.StateEvent,
+	} {
+		if value != "" {
+			body[key] = value
+		}
+	}
+
+	if o.AssigneeIds != nil {
+		body["assignee_ids"] = o.AssigneeIds
+	}
+	if o.ReviewerIds != nil {
+		body["reviewer_ids"] = o.ReviewerIds
+	}
+	if o.Labels != nil {
+		body["labels"] = strings.Join(o.Labels, ",")
+	}
+	if o.MilestoneId != nil {
+		body["milestone_id"] = *o.MilestoneId
+	}
+	if o.RemoveSourceBranch != nil {
+		body["remove_source_branch"] = *o.RemoveSourceBranch
+	}
+	if o.Squash != nil {
+		body["squash"] = *o.Squash
+	}
+	return body
+}
+
+//UpdateMergeRequest 更新合并请求的标题、描述、指派、标签等
+func (c *Client) UpdateMergeRequest(ctx context.Context, projectId string, mergeRequestIid int, opt UpdateMergeRequestOptions, opts ...RequestOption) (mergeRequest *MergeRequest, err error) {
+	return doJSON[MergeRequest](ctx, c, "PUT", mergeRequestPath(projectId, mergeRequestIid), opt.body(), opts)
+}
+
+//CloseMergeRequest 关闭合并请求
+func (c *Client) CloseMergeRequest(ctx context.Context, projectId string, mergeRequestIid int, opts ...RequestOption) (mergeRequest *MergeRequest, err error) {
+	return c.UpdateMergeRequest(ctx, projectId, mergeRequestIid, UpdateMergeRequestOptions{StateEvent: "close"}, opts...)
+}
+
+//ReopenMergeRequest 重新打开已关闭的合并请求
+func (c *Client) ReopenMergeRequest(ctx context.Context, projectId string, mergeRequestIid int, opts ...RequestOption) (mergeRequest *MergeRequest, err error) {
+	return c.UpdateMergeRequest(ctx, projectId, mergeRequestIid, UpdateMergeRequestOptions{StateEvent: "reopen"}, opts...)
+}
+
+//AcceptMergeRequestOptions 合并的参数，指针为nil时使用合并请求上的设置
+type AcceptMergeRequestOptions struct {
+	MergeCommitMessage        string
+	SquashCommitMessage       string
+	Squash                    *bool
+	ShouldRemoveSourceBranch  *bool
+	MergeWhenPipelineSucceeds bool   //pipeline未完成时等待其成功后自动合并
+	Sha                       string //源分支的最新提交不是sha时合并失败，避免合并未审核的提交
+}
+
+//AcceptMergeRequest 合并，不能合并时返回405（如有冲突或pipeline未通过）或409（sha不一致）
+func (c *Client) AcceptMergeRequest(ctx context.Context, projectId string, mergeRequestIid int, opt AcceptMergeRequestOptions, opts ...RequestOption) (mergeRequest *MergeRequest, err error) {
+	body := map[string]interface{}{}
+	if opt.MergeCommitMessage != "" {
+		body["merge_commit_message"] = opt.MergeCommitMessage
+	}
+	if opt.SquashCommitMessage != "" {
+		body["squash_commit_message"] = opt.SquashCommitMessage
+	}
+	if opt.Squash != nil {
+		body["squash"] = *opt.Squash
+	}
+	if opt.ShouldRemoveSourceBranch != nil {
+		body["should_remove_source_branch"] = *opt.ShouldRemoveSourceBranch
+	}
+	if opt.MergeWhenPipelineSucceeds {
+		body["merge_when_pipeline_succeeds"] = true
+	}
+	if opt.Sha != "" {
+		body["sha"] = opt.Sha
+	}
+	return doJSON[MergeRequest](ctx, c, "PUT", mergeRequestPath(projectId, mergeRequestIid)+"/merge", body, opts)
+}
+
+/*
+RebaseMergeRequest 将源分支rebase到目标分支上，rebase在后台进行，
+通过GetMergeRequest查看RebaseInProgress和MergeError获取结果
+*/
+func (c *Client) RebaseMergeRequest(ctx context.Context, projectId string, mergeRequestIid int, skipCI bool, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "PUT", mergeRequestPath(projectId, mergeRequestIid)+"/rebase", opts)
+	defer req.Close()
+
+	if skipCI {
+		req.Param("skip_ci", "true")
+	}
+
+	_, err = req.Do(nil)
+	return
+}
@@ -0,0 +1,128 @@
+package gitlab
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+//Milestone 项目或组的里程碑，里程碑相关的接口使用Id而不是Iid定位
+type Milestone struct {
+	Id          int        `json:"id"`
+	Iid         int        `json:"iid"` //项目内的编号
+	ProjectId   int        `json:"project_id"`
+	GroupId     int        `json:"group_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`      //active或closed
+	StartDate   string     `json:"start_date"` //格式为2006-01-02
+	DueDate     string     `json:"due_date"`   //格式为2006-01-02
+	Expired     bool       `json:"expired"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	WebUrl      string     `json:"web_url"`
+}
+
+//里程碑接口的路径
+func milestonePath(projectId string, milestoneId int) string {
+	return projectPath(projectId) + "/milestones/" + strconv.Itoa(milestoneId)
+}
+
+//ListMilestonesOptions 里程碑列表的过滤条件
+type ListMilestonesOptions struct {
+	ListOptions
+	State  string //active或closed，为空时返回全部
+	Title  string //按标题精确匹配
+	Search string //按标题和描述搜索
+	Iids   []int
+}
+
+func (o ListMilestonesOptions) setParams(req *request) {
+	for key, value := range map[string]string{
+		"state":  o.State,
+		"title":  o.Title,
+		"search": o.Search,
+	} {
+		if value != "" {
+			req.Param(key, value)
+		}
+	}
+	for _, iid := range o.Iids {
+		req.Param("iids[]", strconv.Itoa(iid))
+	}
+}
+
+//ListMilestones 列出项目的里程碑，自动获取所有分页
+func (c *Client) ListMilestones(ctx context.Context, projectId string, opt ListMilestonesOptions, opts ...RequestOption) (milestones []Milestone, err error) {
+	return listAll[Milestone](ctx, c, projectPath(projectId)+"/milestones", opt.ListOptions, opt.setParams, opts)
+}
+
+//GetMilestone 获取单个里程碑
+func (c *Client) GetMilestone(ctx context.Context, projectId string, milestoneId int, opts ...RequestOption) (milestone *Milestone, err error) {
+	return doJSON[Milestone](ctx, c, "GET", milestonePath(projectId, milestoneId), nil, opts)
+}
+
+//MilestoneOptions 创建或更新里程碑的参数，更新时为空的字段不修改
+type MilestoneOptions struct {
+	Title       string
+	Description string
+	StartDate   string //格式为2006-01-02
+	DueDate     string //格式为2006-01-02
+	StateEvent  string //close或activate，只在更新时有效
+}
+
+func (o MilestoneOptions) body() map[string]string {
+	body := map[string]string{}
+	for key, value := range map[string]string{
+		"title":       o.Title,
+		"description": o.Description,
+		"start_date":  o.StartDate,
+		"due_date":    o.DueDate,
+		"state_event": o.StateEvent,
+	} {
+		if value != "" {
+			body[key] = value
+		}
+	}
+	return body
+}
+
+//CreateMilestone 创建里程碑，Title必须设置
+func (c *Client) CreateMilestone(ctx context.Context, projectId string, opt MilestoneOptions, opts ...RequestOption) (milestone *Milestone, err error) {
+	opt.StateEvent = ""
+	return doJSON[Milestone](ctx, c, "POST", projectPath(projectId)+"/milestones", opt.body(), opts)
+}
+
+//UpdateMilestone 修改里程碑
+func (c *Client) UpdateMilestone(ctx context.Context, projectId string, milestoneId int, opt MilestoneOptions, opts ...RequestOption) (milestone *Milestone, err error) {
+	return doJSON[Milestone](ctx, c, "PUT", milestonePath(projectId, milestoneId), opt.body(), opts)
+}
+
+//CloseMilestone 关闭里程碑
+func (c *Client) CloseMilestone(ctx context.Context, projectId string, milestoneId int, opts ...RequestOption) (milestone *Milestone, err error) {
+	return c.UpdateMilestone(ctx, projectId, milestoneId, MilestoneOptions{StateEvent: "close"}, opts...)
+}
+
+//ActivateMilestone 重新打开已关闭的里程碑
+func (c *Client) ActivateMilestone(ctx context.Context, projectId string, milestoneId int, opts ...RequestOption) (milestone *Milestone, err error) {
+	return c.UpdateMilestone(ctx, projectId, milestoneId, MilestoneOptions{StateEvent: "activate"}, opts...)
+}
+
+//DeleteMilestone 删除里程碑
+func (c *Client) DeleteMilestone(ctx context.Context, projectId string, milestoneId int, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", milestonePath(projectId, milestoneId), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//ListMilestoneIssues 列出里程碑中的issue，自动获取所有分页
+func (c *Client) ListMilestoneIssues(ctx context.Context, projectId string, milestoneId int, opt ListOptions, opts ...RequestOption) (issues []Issue, err error) {
+	return listAll[Issue](ctx, c, milestonePath(projectId, milestoneId)+"/issues", opt, nil, opts)
+}
+
+//ListMilestoneMergeRequests 列出里程碑中的合并请求，自动获取所有分页
+func (c *Client) ListMilestoneMergeRequests(ctx context.Context, projectId string, milestoneId int, opt ListOptions, opts ...RequestOption) (mergeRequests []MergeRequest, err error) {
+	return listAll[MergeRequest](ctx, c, milestonePath(projectId, milestoneId)+"/merge_requests", opt, nil, opts)
+}
@@ -0,0 +1,125 @@
+package gitlab
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+//Note 合并请求或issue上的评论
+type Note struct {
+	Id           int           `json:"id"`
+	Type         string        `json:"type"` //普通评论为空，DiffNote为diff上的评论，DiscussionNote为讨论中的回复
+	Body         string        `json:"body"`
+	Author       User          `json:"author"`
+	System       bool          `json:"system"` //Gitlab自动生成的记录，如修改标签、推送提交
+	Resolvable   bool          `json:"resolvable"`
+	Resolved     bool          `json:"resolved"`
+	ResolvedBy   *User         `json:"resolved_by"`
+	NoteableType string        `json:"noteable_type"` //MergeRequest或Issue
+	NoteableId   int           `json:"noteable_id"`
+	NoteableIid  int           `json:"noteable_iid"`
+	Position     *NotePosition `json:"position"`
+	CreatedAt    *time.Time    `json:"created_at"`
+	UpdatedAt    *time.Time    `json:"updated_at"`
+}
+
+/*
+NotePosition 评论在diff中的位置
+新增的行只设置NewLine，删除的行只设置OldLine，未修改的行两者都要设置
+*/
+type NotePosition struct {
+	BaseSha      string `json:"base_sha"`
+	StartSha     string `json:"start_sha"`
+	HeadSha      string `json:"head_sha"`
+	PositionType string `json:"position_type"` //text或image
+	OldPath      string `json:"old_path,omitempty"`
+	NewPath      string `json:"new_path,omitempty"`
+	OldLine      int    `json:"old_line,omitempty"`
+	NewLine      int    `json:"new_line,omitempty"`
+}
+
+//Discussion 讨论，包含第一条评论和所有回复
+type Discussion struct {
+	Id             string `json:"id"`
+	IndividualNote bool   `json:"individual_note"` //不能回复的单条评论
+	Notes          []Note `json:"notes"`
+}
+
+//TextPosition 根据合并请求的DiffRefs生成文件某一行的位置
+func (r *DiffRefs) TextPosition(oldPath, newPath string, oldLine, newLine int) *NotePosition {
+	return &NotePosition{
+		BaseSha:      r.BaseSha,
+		StartSha:     r.StartSha,
+		HeadSha:      r.HeadSha,
+		PositionType: "text",
+		OldPath:      oldPath,
+		NewPath:      newPath,
+		OldLine:      oldLine,
+		NewLine:      newLine,
+	}
+}
+
+//ListMergeRequestNotes 列出合并请求的评论，包括系统记录，自动获取所有分页
+func (c *Client) ListMergeRequestNotes(ctx context.Context, projectId string, mergeRequestIid int, opt ListOptions, opts ...RequestOption) (notes []Note, err error) {
+	return listAll[Note](ctx, c, mergeRequestPath(projectId, mergeRequestIid)+"/notes", opt, nil, opts)
+}
+
+//CreateMergeRequestNote 在合并请求上添加评论
+func (c *Client) CreateMergeRequestNote(ctx context.Context, projectId string, mergeRequestIid int, body string, opts ...RequestOption) (note *Note, err error) {
+	return doJSON[Note](ctx, c, "POST", mergeRequestPath(projectId, mergeRequestIid)+"/notes", map[string]string{"body": body}, opts)
+}
+
+//UpdateMergeRequestNote 修改合并请求上的评论
+func (c *Client) UpdateMergeRequestNote(ctx context.Context, projectId string, mergeRequestIid, noteId int, body string, opts ...RequestOption) (note *Note, err error) {
+	return doJSON[Note](ctx, c, "PUT", mergeRequestPath(projectId, mergeRequestIid)+"/notes/"+strconv.Itoa(noteId), map[string]string{"body": body}, opts)
+}
+
+//DeleteMergeRequestNote 删除合并请求上的评论
+func (c *Client) DeleteMergeRequestNote(ctx context.Context, projectId string, mergeRequestIid, noteId int, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", mergeRequestPath(projectId, mergeRequestIid)+"/notes/"+strconv.Itoa(noteId), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//合并请求讨论接口的路径
+func mergeRequestDiscussionPath(projectId string, mergeRequestIid int, discussionId string) string {
+	return mergeRequestPath(projectId, mergeRequestIid) + "/discussions/" + discussionId
+}
+
+//ListMergeRequestDiscussions 列出合并请求的讨论，自动获取所有分页
+func (c *Client) ListMergeRequestDiscussions(ctx context.Context, projectId string, mergeRequestIid int, opt ListOptions, opts ...RequestOption) (discussions []Discussion, err error) {
+	return listAll[Discussion](ctx, c, mergeRequestPath(projectId, mergeRequestIid)+"/discussions", opt, nil, opts)
+}
+
+//GetMergeRequestDiscussion 获取单个讨论
+func (c *Client) GetMergeRequestDiscussion(ctx context.Context, projectId string, mergeRequestIid int, discussionId string, opts ...RequestOption) (discussion *Discussion, err error) {
+	return doJSON[Discussion](ctx, c, "GET", mergeRequestDiscussionPath(projectId, mergeRequestIid, discussionId), nil, opts)
+}
+
+/*
+CreateMergeRequestDiscussion 在合并请求上发起讨论，position不为nil时评论在diff的指定行上
+用法：
+	mr, _ := client.GetMergeRequest(ctx, projectId, iid)
+	position := mr.DiffRefs.TextPosition("main.go", "main.go", 0, 42)
+	discussion, err := client.CreateMergeRequestDiscussion(ctx, projectId, iid, "missing error check", position)
+*/
+func (c *Client) CreateMergeRequestDiscussion(ctx context.Context, projectId string, mergeRequestIid int, body string, position *NotePosition, opts ...RequestOption) (discussion *Discussion, err error) {
+	params := map[string]interface{}{"body": body}
+	if position != nil {
+		params["position"] = position
+	}
+	return doJSON[Discussion](ctx, c, "POST", mergeRequestPath(projectId, mergeRequestIid)+"/discussions", params, opts)
+}
+
+//AddMergeRequestDiscussionNote 回复讨论
+func (c *Client) AddMergeRequestDiscussionNote(ctx context.Context, projectId string, mergeRequestIid int, discussionId, body string, opts ...RequestOption) (note *Note, err error) {
+	return doJSON[Note](ctx, c, "POST", mergeRequestDiscussionPath(projectId, mergeRequestIid, discussionId)+"/notes", map[string]string{"body": body}, opts)
+}
+
+//ResolveMergeRequestDiscussion 将讨论标记为已解决，resolved为false时重新打开
+func (c *Client) ResolveMergeRequestDiscussion(ctx context.Context, projectId string, mergeRequestIid int, discussionId string, resolved bool, opts ...RequestOption) (discussion *Discussion, err error) {
+	return doJSON[Discussion](ctx, c, "PUT", mergeRequestDiscussionPath(projectId, mergeRequestIid, discussionId), map[string]bool{"resolved": resolved}, opts)
+}
@@ -0,0 +1,245 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+//自动分页时每页的默认数量，Gitlab允许的最大值为100
+const defaultPerPage = 100
+
+//ListOptions 列表接口的分页参数
+type ListOptions struct {
+	Page       int        //页码，从1开始，为0时使用Gitlab的默认值
+	PerPage    int        //每页数量，为0时使用Gitlab的默认值20
+	Pagination string     //设置为"keyset"时使用keyset分页，需要接口支持
+	OrderBy    string     //排序字段，keyset分页时必须设置
+	Sort       string     //asc或desc
+	Keyset     url.Values //keyset分页下一页的参数，由Iterator根据Link Header自动填充
+}
+
+//PageInfo 从返回的Header中解析出的分页信息
+type PageInfo struct {
+	Page       int    //X-Page
+	PerPage    int    //X-Per-Page
+	NextPage   int    //X-Next-Page，没有下一页时为0
+	PrevPage   int    //X-Prev-Page
+	TotalPages int    //X-Total-Pages，数据量过大时Gitlab不返回
+	Total      int    //X-Total，数据量过大时Gitlab不返回
+	NextLink   string //Link Header中rel="next"的地址，keyset分页依赖该地址
+}
+
+//HasNext 是否还有下一页
+func (p *PageInfo) HasNext() bool {
+	return p.NextPage > 0 || p.NextLink != ""
+}
+
+//将分页参数添加到请求中
+func (o ListOptions) apply(req *request) {
+	params := url.Values{}
+
+	if o.Page > 0 {
+		params.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PerPage > 0 {
+		params.Set("per_page", strconv.Itoa(o.PerPage))
+	}
+	if o.Pagination != "" {
+		params.Set("pagination", o.Pagination)
+	}
+	if o.OrderBy != "" {
+		params.Set("order_by", o.OrderBy)
+	}
+	if o.Sort != "" {
+		params.Set("sort", o.Sort)
+	}
+	for key, values := range o.Keyset {
+		params[key] = values
+	}
+
+	for key, values := range params {
+		for _, value := range values {
+			req.Param(key, value)
+		}
+	}
+}
+
+func parsePageInfo(header http.Header) *PageInfo {
+	atoi := func(key string) int {
+		n, _ := strconv.Atoi(header.Get(key))
+		return n
+	}
+
+	return &PageInfo{
+		Page:       atoi("X-Page"),
+		PerPage:    atoi("X-Per-Page"),
+		NextPage:   atoi("X-Next-Page"),
+		PrevPage:   atoi("X-Prev-Page"),
+		TotalPages: atoi("X-Total-Pages"),
+		Total:      atoi("X-Total"),
+		NextLink:   parseNextLink(header.Get("Link")),
+	}
+}
+
+//解析Link Header，格式：<url>; rel="next", <url>; rel="first"
+func parseNextLink(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+
+		target := strings.Trim(strings.TrimSpace(segments[0]), "<>")
+		for _, attr := range segments[1:] {
+			if strings.TrimSpace(attr) == `rel="next"` {
+				return target
+			}
+		}
+	}
+	return ""
+}
+
+//PageFunc 按照分页参数获取一页数据
+type PageFunc[T any] func(ctx context.Context, opt ListOptions) ([]T, *PageInfo, error)
+
+/*
+Iterator 按需逐页获取列表数据，当前页遍历完后才请求下一页
+用法：
+	it := client.RepoTreeIterator(ctx, projectId, "master", "", gitlab.ListOptions{})
+	for it.Next() {
+		tree := it.Value()
+	}
+	if err := it.Err(); err != nil {
+	}
+*/
+type Iterator[T any] struct {
+	ctx      context.Context
+	opt      ListOptions
+	fetch    PageFunc[T]
+	items    []T
+	index    int
+	pageInfo *PageInfo
+	started  bool
+	err      error
+}
+
+//NewIterator 根据获取单页数据的函数创建Iterator
+func NewIterator[T any](ctx context.Context, opt ListOptions, fetch PageFunc[T]) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:   ctx,
+		opt:   opt,
+		fetch: fetch,
+		index: -1,
+	}
+}
+
+//Next 移动到下一条数据，没有更多数据或出错时返回false
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.index+1 >= len(it.items) {
+		if !it.nextPage() {
+			return false
+		}
+	}
+
+	it.index++
+	return true
+}
+
+//请求下一页，没有下一页或出错时返回false
+func (it *Iterator[T]) nextPage() bool {
+	if it.started {
+		if it.pageInfo == nil || !it.pageInfo.HasNext() {
+			return false
+		}
+
+		if it.pageInfo.NextLink != "" && it.opt.Pagination == "keyset" {
+			next, err := url.Parse(it.pageInfo.NextLink)
+			if err != nil {
+				it.err = err
+				return false
+			}
+			it.opt.Keyset = next.Query()
+			it.opt.Page = 0
+		} else if it.pageInfo.NextPage > 0 {
+			it.opt.Page = it.pageInfo.NextPage
+		} else {
+			return false
+		}
+	}
+
+	items, pageInfo, err := it.fetch(it.ctx, it.opt)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.started = true
+	it.items = items
+	it.index = -1
+	it.pageInfo = pageInfo
+	return true
+}
+
+//Value 返回当前数据
+func (it *Iterator[T]) Value() T {
+	return it.items[it.index]
+}
+
+//PageInfo 返回最近一次请求的分页信息
+func (it *Iterator[T]) PageInfo() *PageInfo {
+	return it.pageInfo
+}
+
+//Err 返回遍历过程中的错误
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+//CollectAll 遍历所有分页，返回全部数据
+func CollectAll[T any](it *Iterator[T]) (items []T, err error) {
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	err = it.Err()
+	return
+}
+
+//params用于添加列表接口的过滤参数，分页参数由listOpt单独控制
+func listPage[T any](ctx context.Context, c *Client, path string, listOpt ListOptions, params func(*request), opts []RequestOption) (items []T, pageInfo *PageInfo, err error) {
+	req := c.newRequest(ctx, "GET", path, opts)
+	defer req.Close()
+
+	if params != nil {
+		params(req)
+	}
+	listOpt.apply(req)
+
+	resp, err := req.Do(&items)
+	if err != nil {
+		return
+	}
+
+	pageInfo = parsePageInfo(resp.Header)
+	return
+}
+
+func listIterator[T any](ctx context.Context, c *Client, path string, listOpt ListOptions, params func(*request), opts []RequestOption) *Iterator[T] {
+	return NewIterator(ctx, listOpt, func(ctx context.Context, listOpt ListOptions) ([]T, *PageInfo, error) {
+		return listPage[T](ctx, c, path, listOpt, params, opts)
+	})
+}
+
+//获取所有分页的数据，未指定每页数量时使用Client.PerPage
+func listAll[T any](ctx context.Context, c *Client, path string, listOpt ListOptions, params func(*request), opts []RequestOption) ([]T, error) {
+	if listOpt.PerPage == 0 {
+		listOpt.PerPage = c.perPage()
+	}
+	return CollectAll(listIterator[T](ctx, c, path, listOpt, params, opts))
+}
@@ -0,0 +1,278 @@
+package gitlab
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+//Pipeline 项目的pipeline，pipeline相关的接口只支持v4
+type Pipeline struct {
+	Id             int        `json:"id"`
+	Iid            int        `json:"iid"`
+	ProjectId      int        `json:"project_id"`
+	Status         string     `json:"status"` //created、waiting_for_resource、preparing、pending、running、success、failed、canceled、skipped、manual、scheduled
+	Source         string     `json:"source"` //push、web、trigger、api、schedule、merge_request_event等
+	Ref            string     `json:"ref"`
+	Sha            string     `json:"sha"`
+	BeforeSha      string     `json:"before_sha"`
+	Tag            bool       `json:"tag"`
+	YamlErrors     string     `json:"yaml_errors"`
+	User           *User      `json:"user"`
+	Duration       int        `json:"duration"` //秒
+	QueuedDuration float64    `json:"queued_duration"`
+	Coverage       string     `json:"coverage"`
+	CreatedAt      *time.Time `json:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at"`
+	StartedAt      *time.Time `json:"started_at"`
+	FinishedAt     *time.Time `json:"finished_at"`
+	WebUrl         string     `json:"web_url"`
+}
+
+//PipelineVariable 创建pipeline时传入的变量
+type PipelineVariable struct {
+	Key          string `json:"key"`
+	Value        string `json:"value"`
+	VariableType string `json:"variable_type,omitempty"` //env_var（默认）或file
+}
+
+//Job pipeline中的job
+type Job struct {
+	Id             int        `json:"id"`
+	Name           string     `json:"name"`
+	Stage          string     `json:"stage"`
+	Status         string     `json:"status"`
+	Ref            string     `json:"ref"`
+	Tag            bool       `json:"tag"`
+	AllowFailure   bool       `json:"allow_failure"`
+	FailureReason  string     `json:"failure_reason"`
+	Duration       float64    `json:"duration"` //秒
+	QueuedDuration float64    `json:"queued_duration"`
+	User           *User      `json:"user"`
+	Commit         *Commit    `json:"commit"`
+	CreatedAt      *time.Time `json:"created_at"`
+	StartedAt      *time.Time `json:"started_at"`
+	FinishedAt     *time.Time `json:"finished_at"`
+	WebUrl         string     `json:"web_url"`
+}
+
+//Finished pipeline是否已结束，manual表示在等待手动触发，不会自动继续，也视为结束
+func (p *Pipeline) Finished() bool {
+	switch p.Status {
+	case "success", "failed", "canceled", "skipped", "manual":
+		return true
+	}
+	return false
+}
+
+//pipeline接口的路径
+func pipelinePath(projectId string, pipelineId int) string {
+	return projectPath(projectId) + "/pipelines/" + strconv.Itoa(pipelineId)
+}
+
+//ListPipelinesOptions pipeline列表的过滤条件
+type ListPipelinesOptions struct {
+	ListOptions
+	Scope         string //running、pending、finished、branches、tags
+	Status        string
+	Source        string
+	Ref           string
+	Sha           string
+	Username      string //触发pipeline的用户
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+}
+
+func (o ListPipelinesOptions) setParams(req *request) {
+	for key, value := range map[string]string{
+		"scope":    o.Scope,
+		"status":   o.Status,
+		"source":   o.Source,
+		"ref":      o.Ref,
+		"sha":      o.Sha,
+		"username": o.Username,
+	} {
+		if value != "" {
+			req.Param(key, value)
+		}
+	}
+
+	if o.UpdatedAfter != nil {
+		req.Param("updated_after", o.UpdatedAfter.Format(time.RFC3339))
+	}
+	if o.UpdatedBefore != nil {
+		req.Param("updated_before", o.UpdatedBefore.Format(time.RFC3339))
+	}
+}
+
+//ListPipelines 列出项目的pipeline，按Id倒序，自动获取所有分页
+func (c *Client) ListPipelines(ctx context.Context, projectId string, opt ListPipelinesOptions, opts ...RequestOption) (pipelines []Pipeline, err error) {
+	return listAll[Pipeline](ctx, c, projectPath(projectId)+"/pipelines", opt.ListOptions, opt.setParams, opts)
+}
+
+//ListPipelinesPage 获取pipeline列表的一页数据
+func (c *Client) ListPipelinesPage(ctx context.Context, projectId string, opt ListPipelinesOptions, opts ...RequestOption) (pipelines []Pipeline, pageInfo *PageInfo, err error) {
+	return listPage[Pipeline](ctx, c, projectPath(projectId)+"/pipelines", opt.ListOptions, opt.setParams, opts)
+}
+
+//GetPipeline 获取单个pipeline
+func (c *Client) GetPipeline(ctx context.Context, projectId string, pipelineId int, opts ...RequestOption) (pipeline *Pipeline, err error) {
+	return doJSON[Pipeline](ctx, c, "GET", pipelinePath(projectId, pipelineId), nil, opts)
+}
+
+//CreatePipeline 在ref（分支名或tag）上创建pipeline
+func (c *Client) CreatePipeline(ctx context.Context, projectId, ref string, variables []PipelineVariable, opts ...RequestOption) (pipeline *Pipeline, err error) {
+	body := map[string]interface{}{
+		"ref": ref,
+	}
+	if len(variables) > 0 {
+		body["variables"] = variables
+	}
+	return doJSON[Pipeline](ctx, c, "POST", projectPath(projectId)+"/pipeline", body, opts)
+}
+
+/*
+TriggerPipeline 使用项目的trigger token在ref上触发pipeline，不需要Client的Token有项目权限
+trigger token在项目的 Settings > CI/CD > Pipeline triggers 中创建
+*/
+func (c *Client) TriggerPipeline(ctx context.Context, projectId, token, ref string, variables map[string]string, opts ...RequestOption) (pipeline *Pipeline, err error) {
+	body := map[string]interface{}{
+		"token": token,
+		"ref":   ref,
+	}
+	if len(variables) > 0 {
+		body["variables"] = variables
+	}
+	return doJSON[Pipeline](ctx, c, "POST", projectPath(projectId)+"/trigger/pipeline", body, opts)
+}
+
+//CancelPipeline 取消pipeline中所有未完成的job
+func (c *Client) CancelPipeline(ctx context.Context, projectId string, pipelineId int, opts ...RequestOption) (pipeline *Pipeline, err error) {
+	return doJSON[Pipeline](ctx, c, "POST", pipelinePath(projectId, pipelineId)+"/cancel", nil, opts)
+}
+
+//RetryPipeline 重试pipeline中失败或取消的job
+func (c *Client) RetryPipeline(ctx context.Context, projectId string, pipelineId int, opts ...RequestOption) (pipeline *Pipeline, err error) {
+	return doJSON[Pipeline](ctx, c, "POST", pipelinePath(projectId, pipelineId)+"/retry", nil, opts)
+}
+
+//DeletePipeline 删除pipeline及其job和日志，需要项目Owner权限
+func (c *Client) DeletePipeline(ctx context.Context, projectId string, pipelineId int, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", pipelinePath(projectId, pipelineId), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//ListPipelineJobsOptions pipeline中job列表的过滤条件
+type ListPipelineJobsOptions struct {
+	ListOptions
+	Scope          []string //只返回这些状态的job，例如 failed、success
+	IncludeRetried bool     //包含被重试过的job
+}
+
+func (o ListPipelineJobsOptions) setParams(req *request) {
+	for _, scope := range o.Scope {
+		req.Param("scope[]", scope)
+	}
+	if o.IncludeRetried {
+		req.Param("include_retried", "true")
+	}
+}
+
+//ListPipelineJobs 列出pipeline中的job，自动获取所有分页
+func (c *Client) ListPipelineJobs(ctx context.Context, projectId string, pipelineId int, opt ListPipelineJobsOptions, opts ...RequestOption) (jobs []Job, err error) {
+	return listAll[Job](ctx, c, pipelinePath(projectId, pipelineId)+"/jobs", opt.ListOptions, opt.setParams, opts)
+}
+
+//WaitPipelineOptions 等待pipeline结束的参数
+type WaitPipelineOptions struct {
+	MinInterval time.Duration   //第一次查询前的等待时间，之后每次翻倍，为0时使用2秒
+	MaxInterval time.Duration   //查询间隔的上限，为0时使用30秒
+	OnStatus    func(*Pipeline) //pipeline状态变化时调用，可用于打印进度
+}
+
+//PipelineResult pipeline结束时的状态和每个job的结果
+type PipelineResult struct {
+	Pipeline *Pipeline
+	Jobs     []Job
+}
+
+//Succeeded pipeline是否成功
+func (r *PipelineResult) Succeeded() bool {
+	return r.Pipeline != nil && r.Pipeline.Status == "success"
+}
+
+//FailedJobs 失败且不允许失败的job
+func (r *PipelineResult) FailedJobs() (jobs []Job) {
+	for _, job := range r.Jobs {
+		if job.Status == "failed" && !job.AllowFailure {
+			jobs = append(jobs, job)
+		}
+	}
+	return
+}
+
+/*
+WaitForPipeline 轮询pipeline直到结束（见Pipeline.Finished），返回最终状态和每个job的结果
+查询间隔从MinInterval开始翻倍直到MaxInterval，状态变化时重新从MinInterval开始
+pipeline失败不会返回error，通过PipelineResult.Succeeded判断；ctx取消或超时时返回ctx的错误和最近一次查询到的pipeline
+用法：
+	pipeline, _ := client.CreatePipeline(ctx, projectId, "master", nil)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+	result, err := client.WaitForPipeline(ctx, projectId, pipeline.Id, gitlab.WaitPipelineOptions{})
+	if err == nil && !result.Succeeded() {
+		for _, job := range result.FailedJobs() {
+			log.Printf("job %s failed: %s %s", job.Name, job.FailureReason, job.WebUrl)
+		}
+	}
+*/
+func (c *Client) WaitForPipeline(ctx context.Context, projectId string, pipelineId int, opt WaitPipelineOptions, opts ...RequestOption) (result *PipelineResult, err error) {
+	minInterval := opt.MinInterval
+	if minInterval <= 0 {
+		minInterval = 2 * time.Second
+	}
+	maxInterval := opt.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = 30 * time.Second
+	}
+	if maxInterval < minInterval {
+		maxInterval = minInterval
+	}
+
+	result = &PipelineResult{}
+	interval := minInterval
+	status := ""
+
+	for {
+		pipeline, e := c.GetPipeline(ctx, projectId, pipelineId, opts...)
+		if e != nil {
+			err = e
+			return
+		}
+		result.Pipeline = pipeline
+
+		if pipeline.Status != status {
+			status = pipeline.Status
+			interval = minInterval
+			if opt.OnStatus != nil {
+				opt.OnStatus(pipeline)
+			}
+		}
+
+		if pipeline.Finished() {
+			result.Jobs, err = c.ListPipelineJobs(ctx, projectId, pipelineId, ListPipelineJobsOptions{}, opts...)
+			return
+		}
+
+		if err = sleepContext(ctx, interval); err != nil {
+			return
+		}
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
@@ -0,0 +1,500 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Project 项目的完整信息，兼容v3和v4的返回内容
+type Project struct {
+	ProjectId            int              `json:"id"`
+	Name                 string           `json:"name"`
+	NameWithNamespace    string           `json:"name_with_namespace"`
+	Path                 string           `json:"path"`
+	PathWithNamespace    string           `json:"path_with_namespace"`
+	Description          string           `json:"description"`
+	DefaultBranch        string           `json:"default_branch"`
+	Visibility           string           `json:"visibility"`       //private、internal、public，v3根据visibility_level补全
+	VisibilityLevel      int              `json:"visibility_level"` //v3: 0 private、10 internal、20 public
+	Public               bool             `json:"public"`           //v3
+	SshUrlToRepo         string           `json:"ssh_url_to_repo"`
+	HttpUrlToRepo        string           `json:"http_url_to_repo"`
+	WebUrl               string           `json:"web_url"`
+	ReadmeUrl            string           `json:"readme_url"`
+	AvatarUrl            string           `json:"avatar_url"`
+	Namespace            ProjectNamespace `json:"namespace"`
+	Owner                *User            `json:"owner"`
+	TagList              []string         `json:"tag_list"`
+	Topics               []string         `json:"topics"`
+	Archived             bool             `json:"archived"`
+	EmptyRepo            bool             `json:"empty_repo"`
+	CreatorId            int              `json:"creator_id"`
+	ForksCount           int              `json:"forks_count"`
+	StarCount            int              `json:"star_count"`
+	OpenIssuesCount      int              `json:"open_issues_count"`
+	IssuesEnabled        bool             `json:"issues_enabled"`
+	MergeRequestsEnabled bool             `json:"merge_requests_enabled"`
+	WikiEnabled          bool             `json:"wiki_enabled"`
+	JobsEnabled          bool             `json:"jobs_enabled"`
+	SnippetsEnabled      bool             `json:"snippets_enabled"`
+	ForkedFromProject    *ForkedProject   `json:"forked_from_project"`
+	CreatedAt            *time.Time       `json:"created_at"`
+	LastActivityAt       *time.Time       `json:"last_activity_at"`
+}
+
+//ProjectNamespace 项目所在的namespace，kind为user或group
+type ProjectNamespace struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Kind     string `json:"kind"`
+	FullPath string `json:"full_path"`
+}
+
+//ForkedProject fork来源项目的简要信息
+type ForkedProject struct {
+	ProjectId         int    `json:"id"`
+	Name              string `json:"name"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebUrl            string `json:"web_url"`
+}
+
+//ProjectInfo 兼容旧的类型名称
+type ProjectInfo = Project
+
+//v3没有visibility字段，根据visibility_level补全
+func (p *Project) normalize() {
+	if p.Visibility != "" {
+		return
+	}
+
+	switch p.VisibilityLevel {
+	case 20:
+		p.Visibility = "public"
+	case 10:
+		p.Visibility = "internal"
+	default:
+		p.Visibility = "private"
+	}
+}
+
+func normalizeProjects(projects []Project) []Project {
+	for i := range projects {
+		projects[i].normalize()
+	}
+	return projects
+}
+
+//Bool 返回v的指针，用于设置可选的bool参数
+func Bool(v bool) *bool {
+	return &v
+}
+
+//Int 返回v的指针，用于设置可选的int参数
+func Int(v int) *int {
+	return &v
+}
+
+//ListProjectsOptions 项目列表的过滤条件，排序使用ListOptions.OrderBy和Sort
+type ListProjectsOptions struct {
+	ListOptions
+	Search     string //按名称搜索
+	Owned      bool   //只返回当前用户拥有的项目
+	Membership bool   //只返回当前用户是成员的项目
+	Starred    bool   //只返回当前用户star的项目
+	Visibility string //private、internal、public
+	Archived   *bool  //为nil时不过滤
+	Simple     bool   //只返回简要信息
+
+	//以下只用于ListGroupProjects
+	IncludeSubgroups bool
+}
+
+func (o ListProjectsOptions) setParams(req *request) {
+	if o.Search != "" {
+		req.Param("search", o.Search)
+	}
+	if o.Owned {
+		req.Param("owned", "true")
+	}
+	if o.Membership {
+		req.Param("membership", "true")
+	}
+	if o.Starred {
+		req.Param("starred", "true")
+	}
+	if o.Visibility != "" {
+		req.Param("visibility", o.Visibility)
+	}
+	if o.Archived != nil {
+		req.Param("archived", strconv.FormatBool(*o.Archived))
+	}
+	if o.Simple {
+		req.Param("simple", "true")
+	}
+	if o.IncludeSubgroups {
+		req.Param("include_subgroups", "true")
+	}
+}
+
+//v3使用/projects/owned和/projects/starred代替owned和starred参数
+func (c *Client) projectsPath(ctx context.Context, opt ListProjectsOptions) string {
+	if !c.isV4(ctx) {
+		if opt.Owned {
+			return "/projects/owned"
+		}
+		if opt.Starred {
+			return "/projects/starred"
+		}
+	}
+	return "/projects"
+}
+
+//ListProjects 列出当前用户可以访问的项目，自动获取所有分页
+func (c *Client) ListProjects(ctx context.Context, opt ListProjectsOptions, opts ...RequestOption) (projects []Project, err error) {
+	projects, err = listAll[Project](ctx, c, c.projectsPath(ctx, opt), opt.ListOptions, opt.setParams, opts)
+	return normalizeProjects(projects), err
+}
+
+//ListProjectsPage 获取项目列表的一页数据
+func (c *Client) ListProjectsPage(ctx context.Context, opt ListProjectsOptions, opts ...RequestOption) (projects []Project, pageInfo *PageInfo, err error) {
+	projects, pageInfo, err = listPage[Project](ctx, c, c.projectsPath(ctx, opt), opt.ListOptions, opt.setParams, opts)
+	return normalizeProjects(projects), pageInfo, err
+}
+
+//ProjectIterator 逐页遍历项目列表
+func (c *Client) ProjectIterator(ctx context.Context, opt ListProjectsOptions, opts ...RequestOption) *Iterator[Project] {
+	path := c.projectsPath(ctx, opt)
+	return NewIterator(ctx, opt.ListOptions, func(ctx context.Context, listOpt ListOptions) ([]Project, *PageInfo, error) {
+		projects, pageInfo, err := listPage[Project](ctx, c, path, listOpt, opt.setParams, opts)
+		return normalizeProjects(projects), pageInfo, err
+	})
+}
+
+//ListGroupProjects 列出group下的项目，groupId可以是数字ID或group的完整路径，自动获取所有分页
+func (c *Client) ListGroupProjects(ctx context.Context, groupId string, opt ListProjectsOptions, opts ...RequestOption) (projects []Project, err error) {
+	projects, err = listAll[Project](ctx, c, groupPath(groupId)+"/projects", opt.ListOptions, opt.setParams, opts)
+	return normalizeProjects(projects), err
+}
+
+//ListGroupProjectsPage 获取group项目列表的一页数据
+func (c *Client) ListGroupProjectsPage(ctx context.Context, groupId string, opt ListProjectsOptions, opts ...RequestOption) (projects []Project, pageInfo *PageInfo, err error) {
+	projects, pageInfo, err = listPage[Project](ctx, c, groupPath(groupId)+"/projects", opt.ListOptions, opt.setParams, opts)
+	return normalizeProjects(projects), pageInfo, err
+}
+
+//GroupProjectIterator 逐页遍历group下的项目
+func (c *Client) GroupProjectIterator(ctx context.Context, groupId string, opt ListProjectsOptions, opts ...RequestOption) *Iterator[Project] {
+	return NewIterator(ctx, opt.ListOptions, func(ctx context.Context, listOpt ListOptions) ([]Project, *PageInfo, error) {
+		projects, pageInfo, err := listPage[Project](ctx, c, groupPath(groupId)+"/projects", listOpt, opt.setParams, opts)
+		return normalizeProjects(projects), pageInfo, err
+	})
+}
+
+//CreateProjectOptions 创建项目的参数，Name和Path至少设置一个
+type CreateProjectOptions struct {
+	Name                 string
+	Path                 string
+	NamespaceId          string //namespace的数字ID，NamespaceId和NamespacePath都为空时使用Client.NamespaceId，仍为空时使用当前用户的个人namespace
+	NamespacePath        string //namespace的完整路径，例如 group/subgroup
+	Description          string
+	Visibility           string //private、internal、public，为空时使用private
+	DefaultBranch        string
+	InitializeWithReadme bool
+	ImportUrl            string //从指定的仓库地址导入
+	IssuesEnabled        *bool
+	MergeRequestsEnabled *bool
+	WikiEnabled          *bool
+	JobsEnabled          *bool
+	SnippetsEnabled      *bool
+	LfsEnabled           *bool
+}
+
+//v3使用visibility_level代替visibility
+var visibilityLevels = map[string]int{
+	"private":  0,
+	"internal": 10,
+	"public":   20,
+}
+
+func (o CreateProjectOptions) body(namespaceId string, v4 bool) map[string]interface{} {
+	body := map[string]interface{}{}
+
+	set := func(key, value string) {
+		if value != "" {
+			body[key] = value
+		}
+	}
+	setBool := func(key string, value *bool) {
+		if value != nil {
+			body[key] = *value
+		}
+	}
+
+	set("name", o.Name)
+	set("path", o.Path)
+	set("namespace_id", namespaceId)
+	set("description", o.Description)
+	set("default_branch", o.DefaultBranch)
+	set("import_url", o.ImportUrl)
+
+	visibility := o.Visibility
+	if visibility == "" {
+		visibility = "private"
+	}
+	if v4 {
+		body["visibility"] = visibility
+	} else {
+		body["visibility_level"] = visibilityLevels[visibility]
+		body["public"] = visibility == "public"
+	}
+
+	if o.InitializeWithReadme {
+		body["initialize_with_readme"] = true
+	}
+	setBool("issues_enabled", o.IssuesEnabled)
+	setBool("merge_requests_enabled", o.MergeRequestsEnabled)
+	setBool("wiki_enabled", o.WikiEnabled)
+	setBool("jobs_enabled", o.JobsEnabled)
+	setBool("snippets_enabled", o.SnippetsEnabled)
+	setBool("lfs_enabled", o.LfsEnabled)
+	return body
+}
+
+//GetNamespace 获取namespace信息，namespaceId可以是数字ID或完整路径
+func (c *Client) GetNamespace(ctx context.Context, namespaceId string, opts ...RequestOption) (namespace *ProjectNamespace, err error) {
+	//v3不支持按ID或路径获取，通过搜索匹配
+	if !c.isV4(ctx) {
+		var namespaces []ProjectNamespace
+		namespaces, err = listAll[ProjectNamespace](ctx, c, "/namespaces", ListOptions{}, func(req *request) {
+			if _, e := strconv.Atoi(namespaceId); e != nil {
+				req.Param("search", namespaceId)
+			}
+		}, opts)
+		if err != nil {
+			return
+		}
+
+		for i := range namespaces {
+			ns := &namespaces[i]
+			if strconv.Itoa(ns.Id) == namespaceId || ns.FullPath == namespaceId || ns.Path == namespaceId {
+				namespace = ns
+				return
+			}
+		}
+		err = &APIError{Method: "GET", URL: c.endpoint(APIv3, "/namespaces"), StatusCode: 404, Status: "404 Not Found", Message: "404 Namespace Not Found"}
+		return
+	}
+
+	if strings.Contains(namespaceId, "/") {
+		namespaceId = url.PathEscape(namespaceId)
+	}
+
+	req := c.newRequest(ctx, "GET", "/namespaces/"+namespaceId, opts)
+	defer req.Close()
+
+	namespace = &ProjectNamespace{}
+	_, err = req.Do(namespace)
+	return
+}
+
+//GetCurrentUser 获取Token对应的用户信息，使用WithSudo时为被代替的用户
+func (c *Client) GetCurrentUser(ctx context.Context, opts ...RequestOption) (user *User, err error) {
+	return doJSON[User](ctx, c, "GET", "/user", nil, opts)
+}
+
+//创建项目时使用的namespace ID
+func (c *Client) resolveNamespaceId(ctx context.Context, opt CreateProjectOptions, opts []RequestOption) (namespaceId string, err error) {
+	if opt.NamespaceId != "" {
+		return opt.NamespaceId, nil
+	}
+	if opt.NamespacePath == "" {
+		return c.NamespaceId, nil
+	}
+
+	namespace, err := c.GetNamespace(ctx, opt.NamespacePath, opts...)
+	if err != nil {
+		return
+	}
+	namespaceId = strconv.Itoa(namespace.Id)
+	return
+}
+
+//CreateProjectWithOptions 按照指定的参数创建项目，返回创建后的项目信息
+func (c *Client) CreateProjectWithOptions(ctx context.Context, opt CreateProjectOptions, opts ...RequestOption) (project *Project, err error) {
+	namespaceId, err := c.resolveNamespaceId(ctx, opt, opts)
+	if err != nil {
+		return
+	}
+
+	return c.projectAction(ctx, "POST", "/projects", opt.body(namespaceId, c.isV4(ctx)), opts)
+}
+
+/*
+EnsureProject 项目已存在时直接返回，不存在时创建，created表示是否新建了项目
+项目通过namespace的完整路径和Path（为空时使用Name）查找，并发创建导致名称冲突时返回已存在的项目
+*/
+func (c *Client) EnsureProject(ctx context.Context, opt CreateProjectOptions, opts ...RequestOption) (project *Project, created bool, err error) {
+	namespaceId, err := c.resolveNamespaceId(ctx, opt, opts)
+	if err != nil {
+		return
+	}
+	opt.NamespaceId = namespaceId
+
+	namespacePath := opt.NamespacePath
+	if namespacePath == "" && namespaceId == "" {
+		//没有指定namespace时Gitlab将项目创建在当前用户的个人namespace下，路径为用户名
+		user, e := c.GetCurrentUser(ctx, opts...)
+		if e != nil {
+			err = e
+			return
+		}
+		namespacePath = user.Username
+	} else if namespacePath == "" {
+		namespace, e := c.GetNamespace(ctx, namespaceId, opts...)
+		if e != nil {
+			err = e
+			return
+		}
+		namespacePath = namespace.FullPath
+		if namespacePath == "" {
+			namespacePath = namespace.Path
+		}
+	}
+
+	path := opt.Path
+	if path == "" {
+		path = opt.Name
+	}
+
+	project, err = c.GetProject(ctx, namespacePath+"/"+path, opts...)
+	if err == nil || !IsNotFound(err) {
+		return
+	}
+
+	project, err = c.CreateProjectWithOptions(ctx, opt, opts...)
+	if err == nil {
+		created = true
+		return
+	}
+
+	if isAlreadyTaken(err) {
+		project, err = c.GetProject(ctx, namespacePath+"/"+path, opts...)
+	}
+	return
+}
+
+//名称或路径已被占用，Gitlab返回400和 {"message": {"name": ["has already been taken"]}}
+func isAlreadyTaken(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+		return false
+	}
+
+	for _, messages := range apiErr.Errors {
+		for _, message := range messages {
+			if strings.Contains(message, "already been taken") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//GetProject 获取项目信息，projectId可以是数字ID或"namespace/project"
+func (c *Client) GetProject(ctx context.Context, projectId string, opts ...RequestOption) (project *Project, err error) {
+	return c.projectAction(ctx, "GET", projectPath(projectId), nil, opts)
+}
+
+//DeleteProject 删除项目，v4中Gitlab可能延迟删除（返回202）
+func (c *Client) DeleteProject(ctx context.Context, projectId string, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", projectPath(projectId), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//ArchiveProject 归档项目，归档后仓库变为只读
+func (c *Client) ArchiveProject(ctx context.Context, projectId string, opts ...RequestOption) (project *Project, err error) {
+	return c.projectAction(ctx, "POST", projectPath(projectId)+"/archive", nil, opts)
+}
+
+//UnarchiveProject 取消归档
+func (c *Client) UnarchiveProject(ctx context.Context, projectId string, opts ...RequestOption) (project *Project, err error) {
+	return c.projectAction(ctx, "POST", projectPath(projectId)+"/unarchive", nil, opts)
+}
+
+/*
+TransferProject 将项目转移到其他namespace，namespace可以是数字ID或完整路径
+v3只支持转移到group，namespace必须是group的数字ID
+*/
+func (c *Client) TransferProject(ctx context.Context, projectId, namespace string, opts ...RequestOption) (project *Project, err error) {
+	if !c.isV4(ctx) {
+		return c.projectAction(ctx, "POST", groupPath(namespace)+"/projects/"+projectId, nil, opts)
+	}
+	return c.projectAction(ctx, "PUT", projectPath(projectId)+"/transfer", map[string]string{"namespace": namespace}, opts)
+}
+
+//ForkProjectOptions fork项目的参数，都为空时fork到当前用户的namespace下
+type ForkProjectOptions struct {
+	NamespaceId   string //目标namespace的数字ID
+	NamespacePath string //目标namespace的完整路径
+	Name          string //新项目的名称
+	Path          string //新项目的路径
+	Visibility    string //private、internal、public
+}
+
+//ForkProject fork项目到指定的namespace，返回新项目
+func (c *Client) ForkProject(ctx context.Context, projectId string, opt ForkProjectOptions, opts ...RequestOption) (project *Project, err error) {
+	if !c.isV4(ctx) {
+		namespace := opt.NamespaceId
+		if namespace == "" {
+			namespace = opt.NamespacePath
+		}
+
+		body := map[string]string{}
+		if namespace != "" {
+			body["namespace"] = namespace
+		}
+		return c.projectAction(ctx, "POST", "/projects/fork/"+projectId, body, opts)
+	}
+
+	body := map[string]string{}
+	for key, value := range map[string]string{
+		"namespace_id":   opt.NamespaceId,
+		"namespace_path": opt.NamespacePath,
+		"name":           opt.Name,
+		"path":           opt.Path,
+		"visibility":     opt.Visibility,
+	} {
+		if value != "" {
+			body[key] = value
+		}
+	}
+	return c.projectAction(ctx, "POST", projectPath(projectId)+"/fork", body, opts)
+}
+
+//ListForks 列出项目的所有fork，自动获取所有分页，只支持v4
+func (c *Client) ListForks(ctx context.Context, projectId string, opt ListProjectsOptions, opts ...RequestOption) (projects []Project, err error) {
+	projects, err = listAll[Project](ctx, c, projectPath(projectId)+"/forks", opt.ListOptions, opt.setParams, opts)
+	return normalizeProjects(projects), err
+}
+
+//ListForksPage 获取fork列表的一页数据
+func (c *Client) ListForksPage(ctx context.Context, projectId string, opt ListProjectsOptions, opts ...RequestOption) (projects []Project, pageInfo *PageInfo, err error) {
+	projects, pageInfo, err = listPage[Project](ctx, c, projectPath(projectId)+"/forks", opt.ListOptions, opt.setParams, opts)
+	return normalizeProjects(projects), pageInfo, err
+}
+
+//发送修改项目的请求并返回修改后的项目信息
+func (c *Client) projectAction(ctx context.Context, method, path string, body interface{}, opts []RequestOption) (project *Project, err error) {
+	if project, err = doJSON[Project](ctx, c, method, path, body, opts); err == nil {
+		project.normalize()
+	}
+	return
+}
@@ -0,0 +1,132 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//Limiter 客户端限流接口，golang.org/x/time/rate.Limiter 也满足该接口
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+//RateLimiter 令牌桶限流，批量任务使用它保持在Gitlab实例的配额之内
+type RateLimiter struct {
+	mu       sync.Mutex
+	rate     float64 //每秒产生的令牌数
+	burst    float64 //桶的容量
+	tokens   float64
+	lastTime time.Time
+}
+
+//NewRateLimiter 创建每秒最多ratePerSecond个请求、允许突发burst个请求的限流器
+func NewRateLimiter(ratePerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:     ratePerSecond,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastTime: time.Now(),
+	}
+}
+
+//Wait 等待获取一个令牌，ctx被取消时返回ctx.Err()
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait := l.reserve()
+		if wait <= 0 {
+			return nil
+		}
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+//尝试取出一个令牌，令牌不足时返回需要等待的时间
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	l.tokens += now.Sub(l.lastTime).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.lastTime = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+//rateLimitState 记录Gitlab返回的RateLimit Header，配额用尽后在重置之前暂停发送请求
+type rateLimitState struct {
+	mu      sync.Mutex
+	resetAt time.Time
+}
+
+//根据返回的RateLimit-Remaining和RateLimit-Reset更新配额状态
+func (s *rateLimitState) observe(resp *http.Response) {
+	if resp == nil || resp.Header.Get("RateLimit-Remaining") != "0" {
+		return
+	}
+
+	reset, ok := parseRateLimitReset(resp.Header)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	if reset.After(s.resetAt) {
+		s.resetAt = reset
+	}
+	s.mu.Unlock()
+}
+
+//配额用尽时等待到重置时间
+func (s *rateLimitState) wait(ctx context.Context) error {
+	s.mu.Lock()
+	resetAt := s.resetAt
+	s.mu.Unlock()
+
+	return sleepContext(ctx, time.Until(resetAt))
+}
+
+//RateLimit-Reset 为Unix时间戳
+func parseRateLimitReset(header http.Header) (reset time.Time, ok bool) {
+	seconds, err := strconv.ParseInt(header.Get("RateLimit-Reset"), 10, 64)
+	if err != nil || seconds <= 0 {
+		return
+	}
+	return time.Unix(seconds, 0), true
+}
+
+//等待指定时间，ctx被取消时立即返回ctx.Err()
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
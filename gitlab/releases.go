@@ -0,0 +1,175 @@
+package gitlab
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+//Release 项目的release
+type Release struct {
+	TagName     string        `json:"tag_name"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	CreatedAt   *time.Time    `json:"created_at"`
+	ReleasedAt  *time.Time    `json:"released_at"`
+	Author      User          `json:"author"`
+	Commit      Commit        `json:"commit"`
+	Milestones  []Milestone   `json:"milestones"`
+	Assets      ReleaseAssets `json:"assets"`
+}
+
+//ReleaseAssets release的源码包和附件链接
+type ReleaseAssets struct {
+	Count   int             `json:"count"`
+	Sources []ReleaseSource `json:"sources"`
+	Links   []ReleaseLink   `json:"links"`
+}
+
+//ReleaseSource Gitlab自动生成的源码包
+type ReleaseSource struct {
+	Format string `json:"format"`
+	Url    string `json:"url"`
+}
+
+//ReleaseLink release的附件链接
+type ReleaseLink struct {
+	Id             int    `json:"id"`
+	Name           string `json:"name"`
+	Url            string `json:"url"`
+	DirectAssetUrl string `json:"direct_asset_url"`
+	LinkType       string `json:"link_type"` //other、runbook、image或package
+	External       bool   `json:"external"`
+}
+
+//ReleaseLinkOptions 添加附件链接的参数
+type ReleaseLinkOptions struct {
+	Name            string `json:"name"`
+	Url             string `json:"url"`
+	DirectAssetPath string `json:"direct_asset_path,omitempty"` //永久链接的路径，例如 /binaries/app.tar.gz
+	LinkType        string `json:"link_type,omitempty"`
+}
+
+//ReleaseOptions 创建或更新release的参数
+type ReleaseOptions struct {
+	Name        string
+	Description string               //release说明，支持Markdown
+	Ref         string               //tag不存在时从ref创建tag，只在创建时有效
+	Milestones  []string             //关联的里程碑标题，更新时为nil表示不修改，为空切片表示取消关联
+	ReleasedAt  *time.Time           //为nil时使用当前时间
+	Links       []ReleaseLinkOptions //附件链接，只在创建时有效，更新时使用AddReleaseLink
+}
+
+func (o ReleaseOptions) body(tagName string) map[string]interface{} {
+	body := map[string]interface{}{
+		"tag_name": tagName,
+	}
+	if o.Name != "" {
+		body["name"] = o.Name
+	}
+	if o.Description != "" {
+		body["description"] = o.Description
+	}
+	if o.Ref != "" {
+		body["ref"] = o.Ref
+	}
+	if o.Milestones != nil {
+		body["milestones"] = o.Milestones
+	}
+	if o.ReleasedAt != nil {
+		body["released_at"] = o.ReleasedAt.Format(time.RFC3339)
+	}
+	if len(o.Links) > 0 {
+		body["assets"] = map[string]interface{}{"links": o.Links}
+	}
+	return body
+}
+
+//release接口的路径
+func releasePath(projectId, tagName string) string {
+	return projectPath(projectId) + "/releases/" + url.PathEscape(tagName)
+}
+
+//ListReleases 列出项目的release，按发布时间倒序，自动获取所有分页，只支持v4
+func (c *Client) ListReleases(ctx context.Context, projectId string, opt ListOptions, opts ...RequestOption) (releases []Release, err error) {
+	return listAll[Release](ctx, c, projectPath(projectId)+"/releases", opt, nil, opts)
+}
+
+//GetRelease 获取tag对应的release，只支持v4，v3可以通过GetTag返回的Tag.Release获取说明
+func (c *Client) GetRelease(ctx context.Context, projectId, tagName string, opts ...RequestOption) (release *Release, err error) {
+	return doJSON[Release](ctx, c, "GET", releasePath(projectId, tagName), nil, opts)
+}
+
+/*
+CreateRelease 为tag创建release，tag不存在时需要设置Ref
+v3只支持为已有的tag添加说明，其它参数被忽略
+*/
+func (c *Client) CreateRelease(ctx context.Context, projectId, tagName string, opt ReleaseOptions, opts ...RequestOption) (release *Release, err error) {
+	if !c.isV4(ctx) {
+		return c.tagReleaseAction(ctx, "POST", projectId, tagName, opt.Description, opts)
+	}
+	return doJSON[Release](ctx, c, "POST", projectPath(projectId)+"/releases", opt.body(tagName), opts)
+}
+
+//UpdateRelease 更新release的名称、说明、里程碑和发布时间，为空的字段不修改，v3只支持修改说明
+func (c *Client) UpdateRelease(ctx context.Context, projectId, tagName string, opt ReleaseOptions, opts ...RequestOption) (release *Release, err error) {
+	if !c.isV4(ctx) {
+		return c.tagReleaseAction(ctx, "PUT", projectId, tagName, opt.Description, opts)
+	}
+
+	body := opt.body(tagName)
+	delete(body, "ref")
+	delete(body, "assets")
+	return doJSON[Release](ctx, c, "PUT", releasePath(projectId, tagName), body, opts)
+}
+
+//DeleteRelease 删除release，tag不会被删除，只支持v4
+func (c *Client) DeleteRelease(ctx context.Context, projectId, tagName string, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", releasePath(projectId, tagName), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//AddReleaseLink 为release添加附件链接，只支持v4
+func (c *Client) AddReleaseLink(ctx context.Context, projectId, tagName string, link ReleaseLinkOptions, opts ...RequestOption) (releaseLink *ReleaseLink, err error) {
+	req := c.newRequest(ctx, "POST", releasePath(projectId, tagName)+"/assets/links", opts)
+	defer req.Close()
+
+	req.Body(link)
+
+	releaseLink = &ReleaseLink{}
+	if _, err = req.Do(releaseLink); err != nil {
+		releaseLink = nil
+	}
+	return
+}
+
+//DeleteReleaseLink 删除release的附件链接，只支持v4
+func (c *Client) DeleteReleaseLink(ctx context.Context, projectId, tagName string, linkId int, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", releasePath(projectId, tagName)+"/assets/links/"+strconv.Itoa(linkId), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//v3的release是tag上的说明，转换为Release
+func (r *TagRelease) release() *Release {
+	return &Release{
+		TagName:     r.TagName,
+		Description: r.Description,
+	}
+}
+
+//v3通过tag接口创建或更新release说明
+func (c *Client) tagReleaseAction(ctx context.Context, method, projectId, tagName, description string, opts []RequestOption) (release *Release, err error) {
+	tagRelease, err := doJSON[TagRelease](ctx, c, method, tagPath(projectId, tagName)+"/release", map[string]string{"description": description}, opts)
+	if err != nil {
+		return
+	}
+	release = tagRelease.release()
+	return
+}
@@ -0,0 +1,377 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+//RequestOption 用于调整单个请求的设置
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	timeout time.Duration
+	auth    Authenticator
+	sudo    string
+	retry   bool
+}
+
+//WithTimeout 设置单个请求的超时时间，包括读取返回内容的时间
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = timeout
+	}
+}
+
+//WithRetry 允许单个请求在网络错误和502/503/504时按照Client.Retry重试，用于确定可以重复执行的PUT、POST、DELETE请求
+func WithRetry() RequestOption {
+	return func(o *requestOptions) {
+		o.retry = true
+	}
+}
+
+//WithSudo 以指定用户的身份执行请求，需要管理员Token，提交记录和审计日志都记在该用户名下
+func WithSudo(username string) RequestOption {
+	return func(o *requestOptions) {
+		o.sudo = username
+	}
+}
+
+//WithSudoId 同WithSudo，使用用户ID指定身份
+func WithSudoId(userId int) RequestOption {
+	return WithSudo(strconv.Itoa(userId))
+}
+
+/*
+request 绑定了context的请求，ctx被取消或超时后请求立即中断，
+Response/ToJSON/Bytes/String 此时返回ctx.Err()，便于调用方使用errors.Is判断
+使用完毕后必须调用Close释放context
+*/
+type request struct {
+	client *Client
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	auth   Authenticator
+	method string
+	url    string
+	header http.Header
+	query  url.Values
+	params url.Values
+	body   interface{}
+
+	httpReq *http.Request
+	resp    *http.Response
+	data    []byte
+	err     error
+
+	bodyClosed bool //返回内容已读取或交给Stream的调用方
+
+	hookResp *HookResponse
+	hookDone bool
+}
+
+//Header 设置请求的Header
+func (r *request) Header(key, value string) *request {
+	r.header.Set(key, value)
+	return r
+}
+
+//Param 添加请求参数，GET请求放在URL中，其他请求作为JSON body的字段
+//通过Body设置了请求体时，参数放在URL中
+func (r *request) Param(key, value string) *request {
+	r.params.Add(key, value)
+	return r
+}
+
+//Query 添加URL参数
+func (r *request) Query(key, value string) *request {
+	r.query.Add(key, value)
+	return r
+}
+
+//Body 设置JSON格式的请求体
+func (r *request) Body(v interface{}) *request {
+	r.body = v
+	return r
+}
+
+//Response 发送请求并返回结果，多次调用只发送一次
+func (r *request) Response() (*http.Response, error) {
+	if r.resp == nil && r.err == nil {
+		r.resp, r.err = r.execute()
+	}
+	return r.resp, r.err
+}
+
+func (r *request) execute() (*http.Response, error) {
+	u, err := url.Parse(r.url)
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	for key, values := range r.query {
+		query[key] = append(query[key], values...)
+	}
+
+	payload := r.body
+	if r.method == "GET" || r.method == "HEAD" || payload != nil {
+		for key, values := range r.params {
+			query[key] = append(query[key], values...)
+		}
+	} else if len(r.params) > 0 {
+		payload = jsonParams(r.params)
+	}
+	u.RawQuery = query.Encode()
+
+	var data []byte
+	if payload != nil {
+		if data, err = json.Marshal(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := r.send(u.String(), data)
+	if err != nil {
+		return nil, r.contextErr(err)
+	}
+
+	//Token过期时刷新后重试一次
+	if refresher, ok := r.auth.(TokenRefresher); ok && resp.StatusCode == http.StatusUnauthorized {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		r.runResponseHooks(nil, nil)
+
+		if err = refresher.Refresh(r.ctx); err != nil {
+			return nil, r.contextErr(err)
+		}
+		if resp, err = r.send(u.String(), data); err != nil {
+			return nil, r.contextErr(err)
+		}
+	}
+	return resp, nil
+}
+
+//构建http.Request，添加认证信息后发送
+func (r *request) send(rawurl string, data []byte) (*http.Response, error) {
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(r.ctx, r.method, rawurl, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range r.header {
+		httpReq.Header[key] = values
+	}
+
+	if r.auth != nil {
+		if err = r.auth.Authenticate(r.ctx, httpReq); err != nil {
+			return nil, err
+		}
+	}
+	r.httpReq = httpReq
+
+	hooks := r.client.Hooks
+	if len(hooks) == 0 {
+		return r.client.httpClient().Do(httpReq)
+	}
+
+	hookReq := newHookRequest(httpReq, data)
+	for _, hook := range hooks {
+		hook.BeforeRequest(r.ctx, hookReq)
+	}
+
+	start := time.Now()
+	resp, err := r.client.httpClient().Do(httpReq)
+
+	r.hookDone = false
+	r.hookResp = &HookResponse{
+		Request: hookReq,
+		Latency: time.Since(start),
+	}
+	if err != nil {
+		r.runResponseHooks(nil, r.contextErr(err))
+	} else {
+		r.hookResp.StatusCode = resp.StatusCode
+		r.hookResp.Status = resp.Status
+		r.hookResp.Header = redactHeader(resp.Header)
+	}
+	return resp, err
+}
+
+//调用AfterResponse，每次发送只调用一次
+func (r *request) runResponseHooks(body []byte, err error) {
+	if r.hookResp == nil || r.hookDone {
+		return
+	}
+	r.hookDone = true
+
+	r.hookResp.Body = redactBody(body)
+	if err != nil {
+		r.hookResp.Err = err
+	}
+	for _, hook := range r.client.Hooks {
+		hook.AfterResponse(r.ctx, r.hookResp)
+	}
+}
+
+//参数只有一个值时作为字符串，多个值时作为数组
+func jsonParams(params url.Values) map[string]interface{} {
+	m := make(map[string]interface{}, len(params))
+	for key, values := range params {
+		if len(values) == 1 {
+			m[key] = values[0]
+		} else {
+			m[key] = values
+		}
+	}
+	return m
+}
+
+//Bytes 读取全部返回内容
+func (r *request) Bytes() ([]byte, error) {
+	if r.data != nil {
+		return r.data, nil
+	}
+
+	resp, err := r.Response()
+	if err != nil {
+		return nil, err
+	}
+	r.bodyClosed = true
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		err = r.contextErr(err)
+		r.runResponseHooks(nil, err)
+		return nil, err
+	}
+	r.data = data
+	r.runResponseHooks(data, nil)
+	return data, nil
+}
+
+//String 以字符串形式返回全部返回内容
+func (r *request) String() (string, error) {
+	data, err := r.Bytes()
+	return string(data), err
+}
+
+//ToJSON 将返回内容解析到v中
+func (r *request) ToJSON(v interface{}) error {
+	data, err := r.Bytes()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+//Close 释放请求绑定的context，返回内容没有读取时读完并关闭，使连接可以复用
+func (r *request) Close() {
+	if r.resp != nil && !r.bodyClosed {
+		r.bodyClosed = true
+		io.Copy(ioutil.Discard, io.LimitReader(r.resp.Body, maxDrainSize))
+		r.resp.Body.Close()
+	}
+	r.runResponseHooks(nil, nil)
+	r.cancel()
+}
+
+//Close时最多丢弃的返回内容，超过时直接关闭连接
+const maxDrainSize = 64 << 10
+
+//请求因ctx被取消或超时而失败时，返回ctx.Err()代替底层的网络错误
+func (r *request) contextErr(err error) error {
+	if err != nil && r.ctx.Err() != nil {
+		return r.ctx.Err()
+	}
+	return err
+}
+
+//Do 发送请求，返回2xx时将返回内容解析到v中（v为nil时丢弃），否则返回APIError
+func (r *request) Do(v interface{}) (resp *http.Response, err error) {
+	resp, err = r.Response()
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err = r.apiError(resp)
+		return
+	}
+
+	data, err := r.Bytes()
+	if err != nil || v == nil || len(data) == 0 {
+		return
+	}
+	err = json.Unmarshal(data, v)
+	return
+}
+
+//发送请求并将返回的单个资源解析为T，body不为nil时作为JSON请求体，失败时返回nil
+func doJSON[T any](ctx context.Context, c *Client, method, path string, body interface{}, opts []RequestOption) (v *T, err error) {
+	req := c.newRequest(ctx, method, path, opts)
+	defer req.Close()
+
+	if body != nil {
+		req.Body(body)
+	}
+
+	v = new(T)
+	if _, err = req.Do(v); err != nil {
+		v = nil
+	}
+	return
+}
+
+//Stream 发送请求，返回2xx时以流的方式返回内容，关闭返回的io.ReadCloser时释放请求
+//调用Stream后不需要再调用Close
+func (r *request) Stream() (body io.ReadCloser, resp *http.Response, err error) {
+	resp, err = r.Response()
+	if err != nil {
+		r.Close()
+		return
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err = r.apiError(resp)
+		r.Close()
+		return
+	}
+
+	r.bodyClosed = true
+	body = &streamBody{ReadCloser: resp.Body, req: r}
+	return
+}
+
+//streamBody 关闭时同时释放请求绑定的context
+type streamBody struct {
+	io.ReadCloser
+	req *request
+}
+
+func (b *streamBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.req.Close()
+	return err
+}
+
+//读取返回内容并构建APIError
+func (r *request) apiError(resp *http.Response) error {
+	body, err := r.Bytes()
+	if err != nil && r.ctx.Err() != nil {
+		return err
+	}
+	return newAPIError(r.httpReq, resp, body)
+}
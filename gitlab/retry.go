@@ -0,0 +1,195 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//RetryPolicy 请求失败时的重试策略
+type RetryPolicy struct {
+	MaxRetries int           //最大重试次数，0表示不重试
+	MinBackoff time.Duration //第一次重试前的等待时间，之后每次翻倍
+	MaxBackoff time.Duration //单次等待时间的上限，Retry-After和RateLimit-Reset不受此限制
+	Jitter     bool          //在[backoff/2, backoff]之间随机等待，避免多个任务同时重试
+
+	//Methods 网络错误和502/503/504时重试的请求方法，为空时只重试GET、HEAD、OPTIONS
+	//PUT、DELETE等请求可能已经在服务端执行（例如合并、rebase、提交文件），默认不重试，
+	//确定可以重复执行时加入Methods，或者对单个请求使用WithRetry
+	Methods []string
+
+	//CheckRetry 自定义是否重试，为nil时使用默认规则：
+	//网络错误和502/503/504只重试Methods中的请求和使用了WithRetry的请求，429对所有请求都重试
+	CheckRetry func(req *http.Request, resp *http.Response, err error) bool
+}
+
+//DefaultRetryPolicy 默认重试3次，等待时间从500毫秒开始翻倍，最长30秒
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: 500 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+		Jitter:     true,
+	}
+}
+
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if p.CheckRetry != nil {
+		return p.CheckRetry(req, resp, err)
+	}
+
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if !p.retryMethod(req.Method) && !retryForced(req.Context()) {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+//第attempt次重试前的等待时间，attempt从0开始
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header); ok {
+			return wait
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if reset, ok := parseRateLimitReset(resp.Header); ok {
+				return time.Until(reset)
+			}
+		}
+	}
+
+	wait := p.MinBackoff
+	for i := 0; i < attempt && (p.MaxBackoff <= 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+
+	if p.Jitter && wait > 1 {
+		half := wait / 2
+		wait = half + time.Duration(rand.Int63n(int64(wait-half)))
+	}
+	return wait
+}
+
+//网络错误和502/503/504时是否重试该方法的请求
+func (p *RetryPolicy) retryMethod(method string) bool {
+	if len(p.Methods) == 0 {
+		return isSafeMethod(method)
+	}
+	for _, m := range p.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+//不修改服务端数据的请求，重复发送没有副作用
+func isSafeMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+type retryKey struct{}
+
+//请求是否通过WithRetry允许重试
+func retryForced(ctx context.Context) bool {
+	forced, _ := ctx.Value(retryKey{}).(bool)
+	return forced
+}
+
+//Retry-After 可以是秒数或HTTP时间
+func retryAfter(header http.Header) (wait time.Duration, ok bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t), true
+	}
+	return
+}
+
+/*
+retryTransport 在底层Transport之上实现限流和重试：
+发送前等待客户端限流器和Gitlab的RateLimit配额，失败时按照RetryPolicy重试
+*/
+type retryTransport struct {
+	client *Client
+	base   http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	ctx := req.Context()
+
+	//请求体只能读取一次，先缓存起来以便重试时重新发送
+	var body []byte
+	if req.Body != nil {
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err = t.client.waitRateLimit(ctx); err != nil {
+			return
+		}
+
+		r := req.Clone(ctx)
+		if body != nil {
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err = t.base.RoundTrip(r)
+		t.client.rateLimit.observe(resp)
+
+		policy := t.client.Retry
+		if policy == nil || attempt >= policy.MaxRetries || !policy.shouldRetry(r, resp, err) {
+			return
+		}
+
+		wait := policy.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err = sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
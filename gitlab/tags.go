@@ -0,0 +1,113 @@
+package gitlab
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+//Tag tag信息
+type Tag struct {
+	Name      string      `json:"name"`
+	Message   string      `json:"message"` //附注tag的说明，轻量tag为空
+	Target    string      `json:"target"`  //附注tag为tag对象的id，轻量tag为commit id
+	Protected bool        `json:"protected"`
+	Commit    Commit      `json:"commit"`
+	Release   *TagRelease `json:"release"`
+}
+
+//TagRelease tag上的release说明
+type TagRelease struct {
+	TagName     string `json:"tag_name"`
+	Description string `json:"description"`
+}
+
+//ProtectedTag 受保护tag的设置
+type ProtectedTag struct {
+	Name               string         `json:"name"`
+	CreateAccessLevels []BranchAccess `json:"create_access_levels"`
+}
+
+//tag接口的路径，tag名中的"/"需要编码
+func tagPath(projectId, tagName string) string {
+	return projectPath(projectId) + "/repository/tags/" + url.PathEscape(tagName)
+}
+
+//ListTagsOptions tag列表的过滤条件
+type ListTagsOptions struct {
+	ListOptions
+	Search string //按名称搜索，支持^开头和$结尾，只支持v4
+}
+
+func (o ListTagsOptions) setParams(req *request) {
+	if o.Search != "" {
+		req.Param("search", o.Search)
+	}
+}
+
+//ListTags 列出项目的tag，自动获取所有分页
+func (c *Client) ListTags(ctx context.Context, projectId string, opt ListTagsOptions, opts ...RequestOption) (tags []Tag, err error) {
+	return listAll[Tag](ctx, c, projectPath(projectId)+"/repository/tags", opt.ListOptions, opt.setParams, opts)
+}
+
+//ListTagsPage 获取tag列表的一页数据
+func (c *Client) ListTagsPage(ctx context.Context, projectId string, opt ListTagsOptions, opts ...RequestOption) (tags []Tag, pageInfo *PageInfo, err error) {
+	return listPage[Tag](ctx, c, projectPath(projectId)+"/repository/tags", opt.ListOptions, opt.setParams, opts)
+}
+
+//GetTag 获取单个tag的信息
+func (c *Client) GetTag(ctx context.Context, projectId, tagName string, opts ...RequestOption) (tag *Tag, err error) {
+	return doJSON[Tag](ctx, c, "GET", tagPath(projectId, tagName), nil, opts)
+}
+
+//CreateTag 从ref（分支名、tag或commit id）创建tag，message不为空时创建附注tag
+func (c *Client) CreateTag(ctx context.Context, projectId, tagName, ref, message string, opts ...RequestOption) (tag *Tag, err error) {
+	body := map[string]string{
+		"tag_name": tagName,
+		"ref":      ref,
+	}
+	if message != "" {
+		body["message"] = message
+	}
+	return doJSON[Tag](ctx, c, "POST", projectPath(projectId)+"/repository/tags", body, opts)
+}
+
+//DeleteTag 删除tag
+func (c *Client) DeleteTag(ctx context.Context, projectId, tagName string, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", tagPath(projectId, tagName), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
+
+//ListProtectedTags 列出项目的受保护tag，自动获取所有分页，只支持v4
+func (c *Client) ListProtectedTags(ctx context.Context, projectId string, opt ListOptions, opts ...RequestOption) (protected []ProtectedTag, err error) {
+	return listAll[ProtectedTag](ctx, c, projectPath(projectId)+"/protected_tags", opt, nil, opts)
+}
+
+//ProtectTag 保护tag，name可以是通配符，例如 v*，createAccessLevel为nil时使用Gitlab的默认值（Maintainer），只支持v4
+func (c *Client) ProtectTag(ctx context.Context, projectId, name string, createAccessLevel *AccessLevel, opts ...RequestOption) (protected *ProtectedTag, err error) {
+	req := c.newRequest(ctx, "POST", projectPath(projectId)+"/protected_tags", opts)
+	defer req.Close()
+
+	req.Param("name", name)
+	if createAccessLevel != nil {
+		req.Param("create_access_level", strconv.Itoa(int(*createAccessLevel)))
+	}
+
+	protected = &ProtectedTag{}
+	if _, err = req.Do(protected); err != nil {
+		protected = nil
+	}
+	return
+}
+
+//UnprotectTag 取消tag保护，只支持v4
+func (c *Client) UnprotectTag(ctx context.Context, projectId, name string, opts ...RequestOption) (err error) {
+	req := c.newRequest(ctx, "DELETE", projectPath(projectId)+"/protected_tags/"+url.PathEscape(name), opts)
+	defer req.Close()
+
+	_, err = req.Do(nil)
+	return
+}
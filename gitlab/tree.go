@@ -0,0 +1,174 @@
+package gitlab
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//SkipDir WalkRepoTreeFunc返回该错误时跳过当前目录，对文件返回时跳过所在目录的剩余内容
+var SkipDir = fs.SkipDir
+
+//SkipAll WalkRepoTreeFunc返回该错误时停止遍历，WalkRepoTree返回nil
+var SkipAll = fs.SkipAll
+
+//WalkRepoTreeFunc 遍历仓库目录时对每个文件和子目录调用，filepath为相对于仓库根目录的完整路径
+type WalkRepoTreeFunc func(filepath string, tree RepoTree, err error) error
+
+//ListRepoTreeRecursive 递归获取root下的所有文件和子目录，root为空时从根目录开始，自动获取所有分页
+//v4使用recursive参数，v3逐个目录请求
+func (c *Client) ListRepoTreeRecursive(ctx context.Context, projectId, ref, root string, opts ...RequestOption) (repoTrees []RepoTree, err error) {
+	root = cleanTreePath(root)
+
+	if c.isV4(ctx) {
+		return CollectAll(NewIterator(ctx, ListOptions{PerPage: c.perPage()}, func(ctx context.Context, listOpt ListOptions) ([]RepoTree, *PageInfo, error) {
+			return c.listRepoTreePage(ctx, projectId, ref, root, true, listOpt, opts)
+		}))
+	}
+
+	err = c.walkRepoTreeDir(ctx, projectId, ref, root, func(filepath string, tree RepoTree, err error) error {
+		if err != nil {
+			return err
+		}
+		repoTrees = append(repoTrees, tree)
+		return nil
+	}, opts)
+	return
+}
+
+/*
+WalkRepoTree 按照filepath.Walk的方式遍历root下的文件和子目录，同一目录下按名称排序，root本身不会传给fn
+fn返回SkipDir时跳过对应目录，返回SkipAll时停止遍历，返回其它错误时停止遍历并返回该错误
+v4一次递归获取全部内容，v3逐个目录请求，跳过的目录不会被请求
+用法：
+	err := client.WalkRepoTree(ctx, projectId, "master", "", func(filepath string, tree gitlab.RepoTree, err error) error {
+		if err != nil {
+			return err
+		}
+		if tree.Type == "tree" && tree.Name == "vendor" {
+			return gitlab.SkipDir
+		}
+		return nil
+	})
+*/
+func (c *Client) WalkRepoTree(ctx context.Context, projectId, ref, root string, fn WalkRepoTreeFunc, opts ...RequestOption) (err error) {
+	root = cleanTreePath(root)
+
+	if c.isV4(ctx) {
+		var children map[string][]RepoTree
+		err = walkRepoTree(root, func(dir string) ([]RepoTree, error) {
+			if children == nil {
+				repoTrees, err := c.ListRepoTreeRecursive(ctx, projectId, ref, root, opts...)
+				if err != nil {
+					return nil, err
+				}
+
+				children = map[string][]RepoTree{}
+				for _, tree := range repoTrees {
+					parent := path.Dir(tree.Path)
+					if parent == "." {
+						parent = ""
+					}
+					children[parent] = append(children[parent], tree)
+				}
+			}
+			return children[dir], nil
+		}, fn)
+	} else {
+		err = c.walkRepoTreeDir(ctx, projectId, ref, root, fn, opts)
+	}
+
+	if err == SkipDir || err == SkipAll {
+		err = nil
+	}
+	return
+}
+
+/*
+GlobRepoTree 返回root下所有与pattern匹配的文件，匹配规则同path.Match
+pattern不包含"/"时只匹配文件名，例如 *.yml 匹配任意目录下的yml文件；否则匹配完整路径，例如 conf/*.yml
+*/
+func (c *Client) GlobRepoTree(ctx context.Context, projectId, ref, root, pattern string, opts ...RequestOption) (repoTrees []RepoTree, err error) {
+	if _, err = path.Match(pattern, ""); err != nil {
+		return
+	}
+
+	err = c.WalkRepoTree(ctx, projectId, ref, root, func(filepath string, tree RepoTree, err error) error {
+		if err != nil {
+			return err
+		}
+		if tree.Type != "blob" {
+			return nil
+		}
+
+		name := filepath
+		if !strings.Contains(pattern, "/") {
+			name = tree.Name
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			repoTrees = append(repoTrees, tree)
+		}
+		return nil
+	}, opts...)
+	return
+}
+
+//v3逐个目录请求
+func (c *Client) walkRepoTreeDir(ctx context.Context, projectId, ref, root string, fn WalkRepoTreeFunc, opts []RequestOption) error {
+	return walkRepoTree(root, func(dir string) ([]RepoTree, error) {
+		return c.ListRepoTreeByDirectoryContext(ctx, projectId, ref, dir, opts...)
+	}, fn)
+}
+
+//list返回目录下的直接子项，root请求失败时以root调用fn，子目录请求失败时以该子目录调用fn
+func walkRepoTree(root string, list func(dir string) ([]RepoTree, error), fn WalkRepoTreeFunc) error {
+	repoTrees, err := list(root)
+	if err != nil {
+		return fn(root, RepoTree{Name: path.Base(root), Type: "tree", Path: root}, err)
+	}
+	return walkRepoTrees(repoTrees, list, fn)
+}
+
+func walkRepoTrees(repoTrees []RepoTree, list func(dir string) ([]RepoTree, error), fn WalkRepoTreeFunc) error {
+	sort.Slice(repoTrees, func(i, j int) bool {
+		return repoTrees[i].Name < repoTrees[j].Name
+	})
+
+	for _, tree := range repoTrees {
+		if err := fn(tree.Path, tree, nil); err != nil {
+			if err != SkipDir {
+				return err
+			}
+			if tree.Type == "tree" {
+				continue
+			}
+			return nil
+		}
+		if tree.Type != "tree" {
+			continue
+		}
+
+		children, err := list(tree.Path)
+		if err != nil {
+			if err = fn(tree.Path, tree, err); err != nil && err != SkipDir {
+				return err
+			}
+			continue
+		}
+		if err = walkRepoTrees(children, list, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//去掉路径首尾的"/"，根目录为空字符串
+func cleanTreePath(p string) string {
+	p = strings.Trim(p, "/")
+	if p == "" || p == "." {
+		return ""
+	}
+	return path.Clean(p)
+}
@@ -0,0 +1,114 @@
+package gitlab
+
+import (
+	"context"
+	"net/url"
+)
+
+//支持的API版本，APIVersion设置为APIAuto或空字符串时根据/version接口自动检测
+const (
+	APIv3   = "v3"
+	APIv4   = "v4"
+	APIAuto = "auto"
+)
+
+//Version Gitlab的版本信息
+type Version struct {
+	Version  string `json:"version"`
+	Revision string `json:"revision"`
+}
+
+//GetVersion 获取Gitlab的版本信息
+func (c *Client) GetVersion(ctx context.Context, opts ...RequestOption) (version Version, err error) {
+	req := c.newRequest(ctx, "GET", "/version", opts)
+	defer req.Close()
+
+	resp, err := req.Response()
+
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode == 200 {
+		err = req.ToJSON(&version)
+	} else {
+		err = req.apiError(resp)
+	}
+	return
+}
+
+/*
+DetectAPIVersion 检测Gitlab支持的API版本并缓存结果
+v4/version 存在时使用v4（Gitlab 9.0以上），返回404时使用v3
+只缓存检测成功的结果，检测失败时下一个请求会重新检测
+*/
+func (c *Client) DetectAPIVersion(ctx context.Context, opts ...RequestOption) (apiVersion string, err error) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	return c.detectAPIVersion(ctx, opts)
+}
+
+//检测API版本并缓存结果，调用时需要持有versionMu
+func (c *Client) detectAPIVersion(ctx context.Context, opts []RequestOption) (apiVersion string, err error) {
+	req := c.newVersionRequest(ctx, APIv4, "GET", "/version", opts)
+	defer req.Close()
+
+	resp, err := req.Response()
+
+	if err == nil {
+		switch resp.StatusCode {
+		case 200:
+			apiVersion = APIv4
+		case 404:
+			apiVersion = APIv3
+		default:
+			err = req.apiError(resp)
+		}
+	}
+
+	if err == nil {
+		c.detectedVersion = apiVersion
+	}
+	return
+}
+
+/*
+返回请求使用的API版本，需要自动检测时检测成功后不再检测，并发的请求等待正在进行的检测完成
+检测失败时返回检测的错误，由newRequest交给请求返回，不会猜测版本
+*/
+func (c *Client) version(ctx context.Context) (apiVersion string, err error) {
+	if c.APIVersion != "" && c.APIVersion != APIAuto {
+		return c.APIVersion, nil
+	}
+
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	if c.detectedVersion != "" {
+		return c.detectedVersion, nil
+	}
+	return c.detectAPIVersion(ctx, nil)
+}
+
+//检测失败时返回false，请求本身会返回检测的错误
+func (c *Client) isV4(ctx context.Context) bool {
+	apiVersion, _ := c.version(ctx)
+	return apiVersion == APIv4
+}
+
+//文件接口的地址，v3通过file_path参数指定文件，v4将编码后的文件路径放在URL中
+func filesPath(filepath string, v4 bool) string {
+	if v4 {
+		return "/repository/files/" + url.PathEscape(filepath)
+	}
+	return "/repository/files"
+}
+
+//提交文件时分支参数的名称
+func branchParam(v4 bool) string {
+	if v4 {
+		return "branch"
+	}
+	return "branch_name"
+}